@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// adminOnly 校验管理接口令牌，未配置 ADMIN_TOKEN 时管理接口不可用
+func adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := os.Getenv("ADMIN_TOKEN")
+		// 常量时间比较，避免通过响应耗时逐字节猜测令牌
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+			writeJSON(w, http.StatusForbidden, types.NewErrorResponse("禁止访问", "Forbidden"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// reloadHandler 重新加载调度配置
+func reloadHandler(sched *framework.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+
+		if err := sched.Reload(); err != nil {
+			writeJSON(w, http.StatusBadRequest, types.NewErrorResponse("配置重载失败", err.Error()))
+			return
+		}
+		writeJSON(w, http.StatusOK, types.NewSuccessResponse("配置已重载", nil))
+	}
+}
+
+// panicsHandler 返回各路由算子的 panic 次数与隔离状态
+func panicsHandler(sched *framework.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeJSON(w, http.StatusOK, types.NewSuccessResponse("查询成功", sched.PanicStats()))
+	}
+}
+
+// breakersHandler 返回全部熔断器的状态
+func breakersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, types.NewSuccessResponse("查询成功", framework.CircuitBreakers()))
+}
+
+// writeMethodNotAllowed 返回 405，并在 Allow 头中列出可用的方法
+func writeMethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, types.NewErrorResponse("方法不允许", "Method not allowed"))
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
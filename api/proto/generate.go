@@ -0,0 +1,10 @@
+// Package proto 由本目录下的 .proto 文件生成的消息与 gRPC 服务桩代码。
+//
+// 服务端（rpc 包）按 trpc.yaml 为每个服务监听独立端口，以 gRPC 提供服务。
+// 每个 RPC 方法对应一个调度器路由：请求消息的 proto3 JSON 形式（字段名为 lowerCamelCase）
+// 作为路由的请求体或查询参数，路由响应数据按同样的 JSON 形式解码为响应消息。
+// 令牌、traceparent 等通过 gRPC metadata 传递；算子错误转换为 gRPC 状态码，
+// 参数校验失败的字段以 google.rpc.BadRequest 附加在状态详情中。
+package proto
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative auth.proto record.proto reminder_config.proto statistics.proto
@@ -0,0 +1,248 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.3
+// source: reminder_config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_reminder_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reminder_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_reminder_config_proto_rawDescGZIP(), []int{0}
+}
+
+type ReminderConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`        // 每天的提醒开始时间
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`              // 每天的提醒结束时间
+	Interval      int32                  `protobuf:"varint,6,opt,name=interval,proto3" json:"interval,omitempty"`                          // 提醒间隔（分钟）
+	DailyTarget   int32                  `protobuf:"varint,7,opt,name=daily_target,json=dailyTarget,proto3" json:"daily_target,omitempty"` // 每日目标（毫升）
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReminderConfig) Reset() {
+	*x = ReminderConfig{}
+	mi := &file_reminder_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReminderConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReminderConfig) ProtoMessage() {}
+
+func (x *ReminderConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_reminder_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReminderConfig.ProtoReflect.Descriptor instead.
+func (*ReminderConfig) Descriptor() ([]byte, []int) {
+	return file_reminder_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReminderConfig) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReminderConfig) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReminderConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ReminderConfig) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ReminderConfig) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ReminderConfig) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *ReminderConfig) GetDailyTarget() int32 {
+	if x != nil {
+		return x.DailyTarget
+	}
+	return 0
+}
+
+func (x *ReminderConfig) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReminderConfig) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_reminder_config_proto protoreflect.FileDescriptor
+
+const file_reminder_config_proto_rawDesc = "" +
+	"\n" +
+	"\x15reminder_config.proto\x12\x17water_reminder.reminder\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10GetConfigRequest\"\xfa\x02\n" +
+	"\x0eReminderConfig\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\x129\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1a\n" +
+	"\binterval\x18\x06 \x01(\x05R\binterval\x12!\n" +
+	"\fdaily_target\x18\a \x01(\x05R\vdailyTarget\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\xda\x01\n" +
+	"\x15ReminderConfigService\x12_\n" +
+	"\tGetConfig\x12).water_reminder.reminder.GetConfigRequest\x1a'.water_reminder.reminder.ReminderConfig\x12`\n" +
+	"\fUpdateConfig\x12'.water_reminder.reminder.ReminderConfig\x1a'.water_reminder.reminder.ReminderConfigB9Z7github.com/zhanghuachuan/water-reminder/api/proto;protob\x06proto3"
+
+var (
+	file_reminder_config_proto_rawDescOnce sync.Once
+	file_reminder_config_proto_rawDescData []byte
+)
+
+func file_reminder_config_proto_rawDescGZIP() []byte {
+	file_reminder_config_proto_rawDescOnce.Do(func() {
+		file_reminder_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reminder_config_proto_rawDesc), len(file_reminder_config_proto_rawDesc)))
+	})
+	return file_reminder_config_proto_rawDescData
+}
+
+var file_reminder_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_reminder_config_proto_goTypes = []any{
+	(*GetConfigRequest)(nil),      // 0: water_reminder.reminder.GetConfigRequest
+	(*ReminderConfig)(nil),        // 1: water_reminder.reminder.ReminderConfig
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_reminder_config_proto_depIdxs = []int32{
+	2, // 0: water_reminder.reminder.ReminderConfig.start_time:type_name -> google.protobuf.Timestamp
+	2, // 1: water_reminder.reminder.ReminderConfig.end_time:type_name -> google.protobuf.Timestamp
+	2, // 2: water_reminder.reminder.ReminderConfig.created_at:type_name -> google.protobuf.Timestamp
+	2, // 3: water_reminder.reminder.ReminderConfig.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 4: water_reminder.reminder.ReminderConfigService.GetConfig:input_type -> water_reminder.reminder.GetConfigRequest
+	1, // 5: water_reminder.reminder.ReminderConfigService.UpdateConfig:input_type -> water_reminder.reminder.ReminderConfig
+	1, // 6: water_reminder.reminder.ReminderConfigService.GetConfig:output_type -> water_reminder.reminder.ReminderConfig
+	1, // 7: water_reminder.reminder.ReminderConfigService.UpdateConfig:output_type -> water_reminder.reminder.ReminderConfig
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_reminder_config_proto_init() }
+func file_reminder_config_proto_init() {
+	if File_reminder_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reminder_config_proto_rawDesc), len(file_reminder_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reminder_config_proto_goTypes,
+		DependencyIndexes: file_reminder_config_proto_depIdxs,
+		MessageInfos:      file_reminder_config_proto_msgTypes,
+	}.Build()
+	File_reminder_config_proto = out.File
+	file_reminder_config_proto_goTypes = nil
+	file_reminder_config_proto_depIdxs = nil
+}
@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: reminder_config.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReminderConfigService_GetConfig_FullMethodName    = "/water_reminder.reminder.ReminderConfigService/GetConfig"
+	ReminderConfigService_UpdateConfig_FullMethodName = "/water_reminder.reminder.ReminderConfigService/UpdateConfig"
+)
+
+// ReminderConfigServiceClient is the client API for ReminderConfigService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReminderConfigService 对应 /get_reminder_config、/update_reminder_config 路由（GET/PUT /reminder_config）
+type ReminderConfigServiceClient interface {
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ReminderConfig, error)
+	UpdateConfig(ctx context.Context, in *ReminderConfig, opts ...grpc.CallOption) (*ReminderConfig, error)
+}
+
+type reminderConfigServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReminderConfigServiceClient(cc grpc.ClientConnInterface) ReminderConfigServiceClient {
+	return &reminderConfigServiceClient{cc}
+}
+
+func (c *reminderConfigServiceClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*ReminderConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReminderConfig)
+	err := c.cc.Invoke(ctx, ReminderConfigService_GetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reminderConfigServiceClient) UpdateConfig(ctx context.Context, in *ReminderConfig, opts ...grpc.CallOption) (*ReminderConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReminderConfig)
+	err := c.cc.Invoke(ctx, ReminderConfigService_UpdateConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReminderConfigServiceServer is the server API for ReminderConfigService service.
+// All implementations must embed UnimplementedReminderConfigServiceServer
+// for forward compatibility.
+//
+// ReminderConfigService 对应 /get_reminder_config、/update_reminder_config 路由（GET/PUT /reminder_config）
+type ReminderConfigServiceServer interface {
+	GetConfig(context.Context, *GetConfigRequest) (*ReminderConfig, error)
+	UpdateConfig(context.Context, *ReminderConfig) (*ReminderConfig, error)
+	mustEmbedUnimplementedReminderConfigServiceServer()
+}
+
+// UnimplementedReminderConfigServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReminderConfigServiceServer struct{}
+
+func (UnimplementedReminderConfigServiceServer) GetConfig(context.Context, *GetConfigRequest) (*ReminderConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedReminderConfigServiceServer) UpdateConfig(context.Context, *ReminderConfig) (*ReminderConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateConfig not implemented")
+}
+func (UnimplementedReminderConfigServiceServer) mustEmbedUnimplementedReminderConfigServiceServer() {}
+func (UnimplementedReminderConfigServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeReminderConfigServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReminderConfigServiceServer will
+// result in compilation errors.
+type UnsafeReminderConfigServiceServer interface {
+	mustEmbedUnimplementedReminderConfigServiceServer()
+}
+
+func RegisterReminderConfigServiceServer(s grpc.ServiceRegistrar, srv ReminderConfigServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReminderConfigServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReminderConfigService_ServiceDesc, srv)
+}
+
+func _ReminderConfigService_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReminderConfigServiceServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReminderConfigService_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReminderConfigServiceServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReminderConfigService_UpdateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReminderConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReminderConfigServiceServer).UpdateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReminderConfigService_UpdateConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReminderConfigServiceServer).UpdateConfig(ctx, req.(*ReminderConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReminderConfigService_ServiceDesc is the grpc.ServiceDesc for ReminderConfigService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReminderConfigService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "water_reminder.reminder.ReminderConfigService",
+	HandlerType: (*ReminderConfigServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConfig",
+			Handler:    _ReminderConfigService_GetConfig_Handler,
+		},
+		{
+			MethodName: "UpdateConfig",
+			Handler:    _ReminderConfigService_UpdateConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reminder_config.proto",
+}
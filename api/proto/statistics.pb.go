@@ -0,0 +1,492 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.3
+// source: statistics.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StatisticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Period        string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"` // day（默认）/ week / month / custom
+	Date          string                 `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`     // 基准日期 YYYY-MM-DD
+	Start         string                 `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`   // period 为 custom 时的开始日期
+	End           string                 `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`       // period 为 custom 时的结束日期
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatisticsRequest) Reset() {
+	*x = StatisticsRequest{}
+	mi := &file_statistics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticsRequest) ProtoMessage() {}
+
+func (x *StatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticsRequest.ProtoReflect.Descriptor instead.
+func (*StatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StatisticsRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *StatisticsRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *StatisticsRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *StatisticsRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type RecordInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	DrinkType     string                 `protobuf:"bytes,3,opt,name=drink_type,json=drinkType,proto3" json:"drink_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordInfo) Reset() {
+	*x = RecordInfo{}
+	mi := &file_statistics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordInfo) ProtoMessage() {}
+
+func (x *RecordInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordInfo.ProtoReflect.Descriptor instead.
+func (*RecordInfo) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RecordInfo) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *RecordInfo) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RecordInfo) GetDrinkType() string {
+	if x != nil {
+		return x.DrinkType
+	}
+	return ""
+}
+
+type StatisticsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Period           string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	StartDate        string                 `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate          string                 `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	TotalAmount      float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	DailyAverage     float64                `protobuf:"fixed64,5,opt,name=daily_average,json=dailyAverage,proto3" json:"daily_average,omitempty"`
+	DailyGoal        float64                `protobuf:"fixed64,6,opt,name=daily_goal,json=dailyGoal,proto3" json:"daily_goal,omitempty"`
+	Progress         float64                `protobuf:"fixed64,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	DrinkTypes       map[string]int32       `protobuf:"bytes,8,rep,name=drink_types,json=drinkTypes,proto3" json:"drink_types,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	TimeDistribution map[string]int32       `protobuf:"bytes,9,rep,name=time_distribution,json=timeDistribution,proto3" json:"time_distribution,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	HourlySummary    map[string]int32       `protobuf:"bytes,10,rep,name=hourly_summary,json=hourlySummary,proto3" json:"hourly_summary,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Records          []*RecordInfo          `protobuf:"bytes,11,rep,name=records,proto3" json:"records,omitempty"`
+	Message          string                 `protobuf:"bytes,12,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StatisticsResponse) Reset() {
+	*x = StatisticsResponse{}
+	mi := &file_statistics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticsResponse) ProtoMessage() {}
+
+func (x *StatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticsResponse.ProtoReflect.Descriptor instead.
+func (*StatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatisticsResponse) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *StatisticsResponse) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *StatisticsResponse) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *StatisticsResponse) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetDailyAverage() float64 {
+	if x != nil {
+		return x.DailyAverage
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetDailyGoal() float64 {
+	if x != nil {
+		return x.DailyGoal
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetDrinkTypes() map[string]int32 {
+	if x != nil {
+		return x.DrinkTypes
+	}
+	return nil
+}
+
+func (x *StatisticsResponse) GetTimeDistribution() map[string]int32 {
+	if x != nil {
+		return x.TimeDistribution
+	}
+	return nil
+}
+
+func (x *StatisticsResponse) GetHourlySummary() map[string]int32 {
+	if x != nil {
+		return x.HourlySummary
+	}
+	return nil
+}
+
+func (x *StatisticsResponse) GetRecords() []*RecordInfo {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *StatisticsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DashboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DashboardRequest) Reset() {
+	*x = DashboardRequest{}
+	mi := &file_statistics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardRequest) ProtoMessage() {}
+
+func (x *DashboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardRequest.ProtoReflect.Descriptor instead.
+func (*DashboardRequest) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{3}
+}
+
+type DashboardResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Statistics     *StatisticsResponse    `protobuf:"bytes,1,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	ReminderConfig *ReminderConfig        `protobuf:"bytes,2,opt,name=reminder_config,json=reminderConfig,proto3" json:"reminder_config,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DashboardResponse) Reset() {
+	*x = DashboardResponse{}
+	mi := &file_statistics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardResponse) ProtoMessage() {}
+
+func (x *DashboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_statistics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardResponse.ProtoReflect.Descriptor instead.
+func (*DashboardResponse) Descriptor() ([]byte, []int) {
+	return file_statistics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DashboardResponse) GetStatistics() *StatisticsResponse {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+func (x *DashboardResponse) GetReminderConfig() *ReminderConfig {
+	if x != nil {
+		return x.ReminderConfig
+	}
+	return nil
+}
+
+var File_statistics_proto protoreflect.FileDescriptor
+
+const file_statistics_proto_rawDesc = "" +
+	"\n" +
+	"\x10statistics.proto\x12\x19water_reminder.statistics\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x15reminder_config.proto\"g\n" +
+	"\x11StatisticsRequest\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\tR\x03end\"s\n" +
+	"\n" +
+	"RecordInfo\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1d\n" +
+	"\n" +
+	"drink_type\x18\x03 \x01(\tR\tdrinkType\"\xc5\x06\n" +
+	"\x12StatisticsResponse\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\x12!\n" +
+	"\ftotal_amount\x18\x04 \x01(\x01R\vtotalAmount\x12#\n" +
+	"\rdaily_average\x18\x05 \x01(\x01R\fdailyAverage\x12\x1d\n" +
+	"\n" +
+	"daily_goal\x18\x06 \x01(\x01R\tdailyGoal\x12\x1a\n" +
+	"\bprogress\x18\a \x01(\x01R\bprogress\x12^\n" +
+	"\vdrink_types\x18\b \x03(\v2=.water_reminder.statistics.StatisticsResponse.DrinkTypesEntryR\n" +
+	"drinkTypes\x12p\n" +
+	"\x11time_distribution\x18\t \x03(\v2C.water_reminder.statistics.StatisticsResponse.TimeDistributionEntryR\x10timeDistribution\x12g\n" +
+	"\x0ehourly_summary\x18\n" +
+	" \x03(\v2@.water_reminder.statistics.StatisticsResponse.HourlySummaryEntryR\rhourlySummary\x12?\n" +
+	"\arecords\x18\v \x03(\v2%.water_reminder.statistics.RecordInfoR\arecords\x12\x18\n" +
+	"\amessage\x18\f \x01(\tR\amessage\x1a=\n" +
+	"\x0fDrinkTypesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aC\n" +
+	"\x15TimeDistributionEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a@\n" +
+	"\x12HourlySummaryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x12\n" +
+	"\x10DashboardRequest\"\xb4\x01\n" +
+	"\x11DashboardResponse\x12M\n" +
+	"\n" +
+	"statistics\x18\x01 \x01(\v2-.water_reminder.statistics.StatisticsResponseR\n" +
+	"statistics\x12P\n" +
+	"\x0freminder_config\x18\x02 \x01(\v2'.water_reminder.reminder.ReminderConfigR\x0ereminderConfig2\xec\x01\n" +
+	"\x11StatisticsService\x12l\n" +
+	"\rGetStatistics\x12,.water_reminder.statistics.StatisticsRequest\x1a-.water_reminder.statistics.StatisticsResponse\x12i\n" +
+	"\fGetDashboard\x12+.water_reminder.statistics.DashboardRequest\x1a,.water_reminder.statistics.DashboardResponseB9Z7github.com/zhanghuachuan/water-reminder/api/proto;protob\x06proto3"
+
+var (
+	file_statistics_proto_rawDescOnce sync.Once
+	file_statistics_proto_rawDescData []byte
+)
+
+func file_statistics_proto_rawDescGZIP() []byte {
+	file_statistics_proto_rawDescOnce.Do(func() {
+		file_statistics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_statistics_proto_rawDesc), len(file_statistics_proto_rawDesc)))
+	})
+	return file_statistics_proto_rawDescData
+}
+
+var file_statistics_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_statistics_proto_goTypes = []any{
+	(*StatisticsRequest)(nil),     // 0: water_reminder.statistics.StatisticsRequest
+	(*RecordInfo)(nil),            // 1: water_reminder.statistics.RecordInfo
+	(*StatisticsResponse)(nil),    // 2: water_reminder.statistics.StatisticsResponse
+	(*DashboardRequest)(nil),      // 3: water_reminder.statistics.DashboardRequest
+	(*DashboardResponse)(nil),     // 4: water_reminder.statistics.DashboardResponse
+	nil,                           // 5: water_reminder.statistics.StatisticsResponse.DrinkTypesEntry
+	nil,                           // 6: water_reminder.statistics.StatisticsResponse.TimeDistributionEntry
+	nil,                           // 7: water_reminder.statistics.StatisticsResponse.HourlySummaryEntry
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*ReminderConfig)(nil),        // 9: water_reminder.reminder.ReminderConfig
+}
+var file_statistics_proto_depIdxs = []int32{
+	8, // 0: water_reminder.statistics.RecordInfo.time:type_name -> google.protobuf.Timestamp
+	5, // 1: water_reminder.statistics.StatisticsResponse.drink_types:type_name -> water_reminder.statistics.StatisticsResponse.DrinkTypesEntry
+	6, // 2: water_reminder.statistics.StatisticsResponse.time_distribution:type_name -> water_reminder.statistics.StatisticsResponse.TimeDistributionEntry
+	7, // 3: water_reminder.statistics.StatisticsResponse.hourly_summary:type_name -> water_reminder.statistics.StatisticsResponse.HourlySummaryEntry
+	1, // 4: water_reminder.statistics.StatisticsResponse.records:type_name -> water_reminder.statistics.RecordInfo
+	2, // 5: water_reminder.statistics.DashboardResponse.statistics:type_name -> water_reminder.statistics.StatisticsResponse
+	9, // 6: water_reminder.statistics.DashboardResponse.reminder_config:type_name -> water_reminder.reminder.ReminderConfig
+	0, // 7: water_reminder.statistics.StatisticsService.GetStatistics:input_type -> water_reminder.statistics.StatisticsRequest
+	3, // 8: water_reminder.statistics.StatisticsService.GetDashboard:input_type -> water_reminder.statistics.DashboardRequest
+	2, // 9: water_reminder.statistics.StatisticsService.GetStatistics:output_type -> water_reminder.statistics.StatisticsResponse
+	4, // 10: water_reminder.statistics.StatisticsService.GetDashboard:output_type -> water_reminder.statistics.DashboardResponse
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_statistics_proto_init() }
+func file_statistics_proto_init() {
+	if File_statistics_proto != nil {
+		return
+	}
+	file_reminder_config_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_statistics_proto_rawDesc), len(file_statistics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_statistics_proto_goTypes,
+		DependencyIndexes: file_statistics_proto_depIdxs,
+		MessageInfos:      file_statistics_proto_msgTypes,
+	}.Build()
+	File_statistics_proto = out.File
+	file_statistics_proto_goTypes = nil
+	file_statistics_proto_depIdxs = nil
+}
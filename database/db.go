@@ -91,9 +91,12 @@ func DeleteTokenFromRedis(userID string) error {
 	return Redis.Client.Del(ctx, "user_token:"+userID).Err()
 }
 
-// IsTokenValid 验证token是否有效
+// IsTokenValid 验证token是否有效，token不存在时返回 false 且不返回错误
 func IsTokenValid(userID, token string) (bool, error) {
 	storedToken, err := GetTokenFromRedis(userID)
+	if err == redis.Nil {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,63 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// DefaultMaxBodyBytes 路由未配置 max_body_bytes 时的请求体大小上限（1MB）
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// DecodeOptions 请求体解码选项
+type DecodeOptions struct {
+	Strict     bool // 拒绝结构体中不存在的字段
+	AllowEmpty bool // 请求体为空时不报错，out 保持原值
+}
+
+// RequestBody 返回调度器缓冲的请求体，可被任意多个算子读取，调用方不应修改
+func RequestBody(ctx context.Context) []byte {
+	exec, _ := ctx.Value(executionKey{}).(*execution)
+	if exec == nil || exec.opts.Request == nil {
+		return nil
+	}
+	return exec.opts.Request.Body
+}
+
+// DecodeBody 将缓冲的 JSON 请求体解码到 out，失败时返回400 ApiError
+func DecodeBody(ctx context.Context, out interface{}, opts DecodeOptions) error {
+	body := RequestBody(ctx)
+	if len(bytes.TrimSpace(body)) == 0 {
+		if opts.AllowEmpty {
+			return nil
+		}
+		return types.NewApiError("请求参数错误", "Request body is empty", http.StatusBadRequest)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if opts.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(out); err != nil {
+		return types.NewApiError("请求参数错误", "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	}
+	// 一个请求体只能包含一个 JSON 值
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return types.NewApiError("请求参数错误", "Invalid request body: unexpected data after JSON value", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// validateBodyLimit 校验路由的请求体大小上限
+func (c *RouteConfig) validateBodyLimit() error {
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("max_body_bytes must not be negative")
+	}
+	return nil
+}
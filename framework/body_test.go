@@ -0,0 +1,188 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// bodyContext 返回 DecodeBody 可读取 body 的上下文
+func bodyContext(body string) context.Context {
+	exec := &execution{opts: ExecuteOptions{Request: NewRequest(http.MethodPost, "/", []byte(body))}}
+	return withExecution(context.Background(), exec)
+}
+
+// wantStatus 校验 err 为指定状态码的 ApiError，status 为 0 时要求没有错误
+func wantStatus(t *testing.T, err error, status int, contains string) {
+	t.Helper()
+	if status == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var apiErr *types.ApiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+		t.Fatalf("err = %v, want status %d", err, status)
+	}
+	if !strings.Contains(apiErr.ErrorMsg, contains) {
+		t.Errorf("error message = %q, want it to contain %q", apiErr.ErrorMsg, contains)
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	type payload struct {
+		Amount int    `json:"amount"`
+		Note   string `json:"note"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		opts       DecodeOptions
+		wantStatus int
+		wantMsg    string
+		want       payload
+	}{
+		{name: "valid", body: `{"amount": 250, "note": "tea"}`, want: payload{Amount: 250, Note: "tea"}},
+		{name: "empty", body: "", wantStatus: http.StatusBadRequest, wantMsg: "Request body is empty"},
+		{name: "whitespace only", body: " \n\t", wantStatus: http.StatusBadRequest, wantMsg: "Request body is empty"},
+		{name: "empty allowed", body: " ", opts: DecodeOptions{AllowEmpty: true}, want: payload{Amount: 1}},
+		{name: "malformed", body: `{"amount": `, wantStatus: http.StatusBadRequest, wantMsg: "Invalid request body"},
+		{name: "wrong type", body: `{"amount": "250"}`, wantStatus: http.StatusBadRequest, wantMsg: "cannot unmarshal string"},
+		{name: "unknown field allowed", body: `{"amount": 250, "extra": 1}`, want: payload{Amount: 250}},
+		{name: "unknown field strict", body: `{"amount": 250, "extra": 1}`, opts: DecodeOptions{Strict: true}, wantStatus: http.StatusBadRequest, wantMsg: `unknown field "extra"`},
+		{name: "trailing value", body: `{"amount": 250} {"amount": 1}`, wantStatus: http.StatusBadRequest, wantMsg: "unexpected data after JSON value"},
+		{name: "trailing garbage", body: `{"amount": 250}]`, wantStatus: http.StatusBadRequest, wantMsg: "unexpected data after JSON value"},
+		{name: "trailing whitespace", body: "{\"amount\": 250}\n", want: payload{Amount: 250}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := payload{Amount: 1}
+			err := DecodeBody(bodyContext(tt.body), &out, tt.opts)
+			wantStatus(t, err, tt.wantStatus, tt.wantMsg)
+			if tt.wantStatus == 0 && out != tt.want {
+				t.Errorf("decoded %+v, want %+v", out, tt.want)
+			}
+		})
+	}
+}
+
+// failingReader 读取到一半时返回错误
+type failingReader struct{ read bool }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, errors.New("connection reset")
+	}
+	r.read = true
+	return copy(p, "{"), nil
+}
+
+func TestLoadBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    func() *Request
+		limit      int64
+		wantStatus int
+		wantMsg    string
+		wantBody   string
+	}{
+		{
+			name: "http body under limit",
+			request: func() *Request {
+				return NewHTTPRequest(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345")))
+			},
+			limit:    10,
+			wantBody: "12345",
+		},
+		{
+			name: "http body at limit",
+			request: func() *Request {
+				return NewHTTPRequest(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("1234567890")))
+			},
+			limit:    10,
+			wantBody: "1234567890",
+		},
+		{
+			name: "http body over limit",
+			request: func() *Request {
+				return NewHTTPRequest(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345678901")))
+			},
+			limit:      10,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantMsg:    "Request body exceeds 10 bytes",
+		},
+		{
+			name: "http read error",
+			request: func() *Request {
+				return NewHTTPRequest(httptest.NewRequest(http.MethodPost, "/", io.NopCloser(&failingReader{})))
+			},
+			limit:      10,
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    "connection reset",
+		},
+		{
+			name:     "no http body",
+			request:  func() *Request { return NewHTTPRequest(httptest.NewRequest(http.MethodGet, "/", nil)) },
+			limit:    10,
+			wantBody: "",
+		},
+		{
+			name:       "buffered rpc body over limit",
+			request:    func() *Request { return NewRPCRequest(http.MethodPost, "/", nil, []byte(`{"note": "too long"}`)) },
+			limit:      8,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantMsg:    "Request body exceeds 8 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request()
+			err := request.loadBody(tt.limit)
+			wantStatus(t, err, tt.wantStatus, tt.wantMsg)
+			if tt.wantStatus == 0 && string(request.Body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", request.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRouteBodyLimit(t *testing.T) {
+	var ran bool
+	registerTestOperator("body_test_decode", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		ran = true
+		var body map[string]interface{}
+		return ctx, &OperatorResult{Data: body, Error: DecodeBody(ctx, &body, DecodeOptions{})}
+	})
+	s := newTestScheduler(t, `[{"server_name": "/body", "max_body_bytes": 16, "nodes": [{"name": "body_test_decode"}]}]`)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantRan    bool
+	}{
+		{name: "within limit", body: `{"a": 1}`, wantRan: true},
+		{name: "invalid json", body: `{"a": }`, wantStatus: http.StatusBadRequest, wantRan: true},
+		// 超过上限时在执行任何算子之前返回413
+		{name: "over limit", body: `{"a": "0123456789"}`, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = false
+			request := NewHTTPRequest(httptest.NewRequest(http.MethodPost, "/body", strings.NewReader(tt.body)))
+			_, err := s.Run(context.Background(), "/body", ExecuteOptions{Request: request})
+			wantStatus(t, err, tt.wantStatus, "")
+			if ran != tt.wantRan {
+				t.Errorf("operator ran = %v, want %v", ran, tt.wantRan)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// breakerStateValues 状态在指标中的取值
+var breakerStateValues = map[string]float64{
+	BreakerClosed:   0,
+	BreakerOpen:     1,
+	BreakerHalfOpen: 2,
+}
+
+func init() {
+	RegisterOperatorDecorator("circuit_breaker", NewOperatorDecorator("circuit_breaker", NewCircuitBreakerOperator))
+}
+
+// CircuitBreakerConfig circuit_breaker 装饰器配置
+type CircuitBreakerConfig struct {
+	Breaker             string           `json:"breaker"`                // 熔断器名称，同名节点共享状态（如所有依赖 Redis 的节点），默认使用算子名称
+	FailureThreshold    int              `json:"failure_threshold"`      // 连续失败多少次后熔断，默认 5
+	Cooldown            Duration         `json:"cooldown"`               // 熔断后多久进入半开状态，默认 10s
+	HalfOpenMaxRequests int              `json:"half_open_max_requests"` // 半开状态下允许同时试探的请求数，默认 1
+	FailureStatus       []int            `json:"failure_status"`         // 计为失败的 ApiError 状态码，默认全部 5xx
+	Fallback            *BreakerFallback `json:"fallback"`               // 熔断时返回的降级结果，为空时返回503
+}
+
+// BreakerFallback 熔断时的降级结果
+type BreakerFallback struct {
+	Data interface{} `json:"data"`
+}
+
+// BreakerStat 熔断器状态快照
+type BreakerStat struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	FailureThreshold    int        `json:"failureThreshold"`
+	Cooldown            string     `json:"cooldown"`
+}
+
+// CircuitBreaker 连续失败达到阈值后熔断，冷却后进入半开状态试探，试探成功则恢复
+type CircuitBreaker struct {
+	name string
+
+	mu               sync.Mutex
+	state            string
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	threshold        int
+	cooldown         time.Duration
+	halfOpenMax      int
+}
+
+var (
+	circuitBreakers = make(map[string]*CircuitBreaker)
+	breakerMutex    sync.Mutex
+)
+
+// circuitBreaker 返回指定名称的熔断器，配置重载时沿用已有状态并更新参数
+func circuitBreaker(name string, threshold int, cooldown time.Duration, halfOpenMax int) *CircuitBreaker {
+	breakerMutex.Lock()
+	defer breakerMutex.Unlock()
+
+	cb, exists := circuitBreakers[name]
+	if !exists {
+		cb = &CircuitBreaker{name: name, state: BreakerClosed}
+		circuitBreakers[name] = cb
+		breakerState.Set(breakerStateValues[BreakerClosed], name)
+	}
+
+	cb.mu.Lock()
+	cb.threshold, cb.cooldown, cb.halfOpenMax = threshold, cooldown, halfOpenMax
+	cb.mu.Unlock()
+	return cb
+}
+
+// CircuitBreakers 返回全部熔断器的状态，按名称排序
+func CircuitBreakers() []BreakerStat {
+	breakerMutex.Lock()
+	breakers := make([]*CircuitBreaker, 0, len(circuitBreakers))
+	for _, cb := range circuitBreakers {
+		breakers = append(breakers, cb)
+	}
+	breakerMutex.Unlock()
+
+	stats := make([]BreakerStat, 0, len(breakers))
+	for _, cb := range breakers {
+		stats = append(stats, cb.stat(time.Now()))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// allow 判断请求能否通过，probe 表示该请求是半开状态下的试探；
+// 冷却结束时由第一个请求将熔断器切换到半开状态
+func (cb *CircuitBreaker) allow(now time.Time) (allowed, probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == BreakerOpen && now.Sub(cb.openedAt) >= cb.cooldown {
+		cb.setState(BreakerHalfOpen)
+	}
+
+	switch cb.state {
+	case BreakerClosed:
+		return true, false
+	case BreakerHalfOpen:
+		if cb.halfOpenInFlight >= cb.halfOpenMax {
+			return false, false
+		}
+		cb.halfOpenInFlight++
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// record 记录一次通过的请求的结果；状态切换前放行的请求的结果不影响新状态
+func (cb *CircuitBreaker) record(probe, failed bool, now time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe != (cb.state == BreakerHalfOpen) {
+		return
+	}
+	if probe {
+		cb.halfOpenInFlight--
+		if failed {
+			cb.open(now)
+		} else {
+			cb.failures = 0
+			cb.setState(BreakerClosed)
+		}
+		return
+	}
+
+	if !failed {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.state == BreakerClosed && cb.failures >= cb.threshold {
+		cb.open(now)
+	}
+}
+
+func (cb *CircuitBreaker) open(now time.Time) {
+	cb.openedAt = now
+	cb.halfOpenInFlight = 0
+	cb.setState(BreakerOpen)
+}
+
+// setState 切换状态并同步指标，调用方需持有锁
+func (cb *CircuitBreaker) setState(state string) {
+	if cb.state == state {
+		return
+	}
+	cb.state = state
+	breakerState.Set(breakerStateValues[state], cb.name)
+	breakerTransitions.Inc(cb.name, state)
+}
+
+func (cb *CircuitBreaker) stat(now time.Time) BreakerStat {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.state
+	if state == BreakerOpen && now.Sub(cb.openedAt) >= cb.cooldown {
+		state = BreakerHalfOpen // 下一个请求将开始试探
+	}
+	stat := BreakerStat{
+		Name:                cb.name,
+		State:               state,
+		ConsecutiveFailures: cb.failures,
+		FailureThreshold:    cb.threshold,
+		Cooldown:            cb.cooldown.String(),
+	}
+	if cb.state != BreakerClosed {
+		openedAt := cb.openedAt
+		stat.OpenedAt = &openedAt
+	}
+	return stat
+}
+
+// CircuitBreakerOperator circuit_breaker 装饰器包装的算子
+type CircuitBreakerOperator struct {
+	inner    Operator
+	breaker  *CircuitBreaker
+	failures []int
+	fallback *BreakerFallback
+}
+
+// NewCircuitBreakerOperator 创建熔断器包装的算子
+func NewCircuitBreakerOperator(op Operator, config map[string]interface{}) (Operator, error) {
+	var cfg CircuitBreakerConfig
+	if err := DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Breaker == "" {
+		cfg.Breaker = op.Name()
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown.Duration == 0 {
+		cfg.Cooldown.Duration = 10 * time.Second
+	}
+	if cfg.HalfOpenMaxRequests == 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+	if cfg.FailureThreshold < 0 || cfg.HalfOpenMaxRequests < 0 {
+		return nil, fmt.Errorf("circuit breaker thresholds must be positive")
+	}
+
+	return &CircuitBreakerOperator{
+		inner:    op,
+		breaker:  circuitBreaker(cfg.Breaker, cfg.FailureThreshold, cfg.Cooldown.Duration, cfg.HalfOpenMaxRequests),
+		failures: cfg.FailureStatus,
+		fallback: cfg.Fallback,
+	}, nil
+}
+
+func (o *CircuitBreakerOperator) Name() string {
+	return o.inner.Name()
+}
+
+func (o *CircuitBreakerOperator) Unwrap() Operator {
+	return o.inner
+}
+
+func (o *CircuitBreakerOperator) Execute(ctx context.Context, req *Request) (newCtx context.Context, result *OperatorResult) {
+	allowed, probe := o.breaker.allow(time.Now())
+	if !allowed {
+		breakerRejections.Inc(o.breaker.name)
+		if o.fallback != nil {
+			return ctx, &OperatorResult{Data: o.fallback.Data}
+		}
+		return ctx, &OperatorResult{
+			Error: types.NewApiError("服务暂不可用", "Circuit breaker "+o.breaker.name+" is open", http.StatusServiceUnavailable),
+		}
+	}
+
+	// 算子 panic 也计为失败，panic 继续向上交给调度器恢复
+	completed := false
+	defer func() {
+		if !completed {
+			o.breaker.record(probe, true, time.Now())
+		}
+	}()
+
+	newCtx, result = o.inner.Execute(ctx, req)
+	completed = true
+	o.breaker.record(probe, result != nil && o.isFailure(result.Error), time.Now())
+	return newCtx, result
+}
+
+// isFailure 判断错误是否计入熔断：客户端错误（4xx）不计入，其余默认计入
+func (o *CircuitBreakerOperator) isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *types.ApiError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	if len(o.failures) == 0 {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	for _, status := range o.failures {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
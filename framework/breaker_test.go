@@ -0,0 +1,306 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	// step 在 at 时刻发起一次请求：allowed 为期望的放行结果，放行时按 failed 记录结果
+	type step struct {
+		at        time.Duration
+		allowed   bool
+		failed    bool
+		wantState string
+	}
+
+	tests := []struct {
+		name        string
+		halfOpenMax int
+		steps       []step
+	}{
+		{
+			name: "closed to open to half-open to closed",
+			steps: []step{
+				{at: 0, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 1, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 2, allowed: true, failed: true, wantState: BreakerOpen},
+				{at: 5 * time.Second, allowed: false, wantState: BreakerOpen},
+				{at: 12 * time.Second, allowed: true, failed: false, wantState: BreakerClosed},
+				{at: 13 * time.Second, allowed: true, failed: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "success resets consecutive failures",
+			steps: []step{
+				{at: 0, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 1, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 2, allowed: true, failed: false, wantState: BreakerClosed},
+				{at: 3, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 4, allowed: true, failed: true, wantState: BreakerClosed},
+				{at: 5, allowed: true, failed: true, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "failed probe reopens and restarts cooldown",
+			steps: []step{
+				{at: 0, allowed: true, failed: true},
+				{at: 1, allowed: true, failed: true},
+				{at: 2, allowed: true, failed: true, wantState: BreakerOpen},
+				{at: 11 * time.Second, allowed: true, failed: true, wantState: BreakerOpen},
+				{at: 20 * time.Second, allowed: false, wantState: BreakerOpen},
+				{at: 21 * time.Second, allowed: true, failed: false, wantState: BreakerClosed},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := circuitBreaker("breaker_test/"+tt.name, 3, 10*time.Second, 1)
+			start := time.Now()
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				allowed, probe := cb.allow(now)
+				if allowed != s.allowed {
+					t.Fatalf("step %d: allowed = %v, want %v", i, allowed, s.allowed)
+				}
+				if allowed {
+					cb.record(probe, s.failed, now)
+				}
+				if s.wantState != "" {
+					if got := cb.stat(now).State; got != s.wantState {
+						t.Fatalf("step %d: state = %s, want %s", i, got, s.wantState)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	cb := circuitBreaker("breaker_test/half_open_limit", 1, time.Second, 1)
+	start := time.Now()
+	if allowed, probe := cb.allow(start); !allowed || probe {
+		t.Fatal("closed breaker must allow a normal request")
+	}
+	cb.record(false, true, start)
+
+	now := start.Add(time.Second)
+	allowed, probe := cb.allow(now)
+	if !allowed || !probe {
+		t.Fatal("first request after cooldown must be a probe")
+	}
+	if allowed, _ := cb.allow(now); allowed {
+		t.Error("second concurrent probe must be rejected")
+	}
+	if got := cb.stat(now).State; got != BreakerHalfOpen {
+		t.Errorf("state = %s, want %s", got, BreakerHalfOpen)
+	}
+
+	// 熔断前放行的请求在半开状态下返回，不影响试探结果
+	cb.record(false, true, now)
+	if got := cb.stat(now).State; got != BreakerHalfOpen {
+		t.Errorf("stale result changed state to %s", got)
+	}
+	cb.record(probe, false, now)
+	if got := cb.stat(now).State; got != BreakerClosed {
+		t.Errorf("state = %s, want %s", got, BreakerClosed)
+	}
+}
+
+func TestCircuitBreakerOperator(t *testing.T) {
+	var err error
+	inner := &testOperator{name: "breaker_test_inner", execute: func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: err, Data: "live"}
+	}}
+
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		errs      []error // 依次作为内层算子的错误
+		wantState string
+		wantData  interface{} // 最后一次调用的结果数据
+		wantCode  int         // 最后一次调用的错误状态码
+	}{
+		{
+			name:      "client errors do not trip",
+			config:    map[string]interface{}{"failure_threshold": 2},
+			errs:      []error{types.NewApiError("bad", "bad", http.StatusBadRequest), types.NewApiError("bad", "bad", http.StatusBadRequest)},
+			wantState: BreakerClosed,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "open breaker rejects with 503",
+			config:    map[string]interface{}{"failure_threshold": 2},
+			errs:      []error{errors.New("down"), types.NewApiError("down", "down", http.StatusBadGateway), nil},
+			wantState: BreakerOpen,
+			wantCode:  http.StatusServiceUnavailable,
+		},
+		{
+			name:      "open breaker returns fallback",
+			config:    map[string]interface{}{"failure_threshold": 1, "fallback": map[string]interface{}{"data": "cached"}},
+			errs:      []error{errors.New("down"), nil},
+			wantState: BreakerOpen,
+			wantData:  "cached",
+		},
+		{
+			name:      "failure_status limits counted errors",
+			config:    map[string]interface{}{"failure_threshold": 1, "failure_status": []interface{}{503}},
+			errs:      []error{types.NewApiError("down", "down", http.StatusInternalServerError)},
+			wantState: BreakerClosed,
+			wantCode:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["breaker"] = "breaker_test_operator/" + tt.name
+			op, buildErr := NewCircuitBreakerOperator(inner, tt.config)
+			if buildErr != nil {
+				t.Fatal(buildErr)
+			}
+
+			var result *OperatorResult
+			for _, e := range tt.errs {
+				err = e
+				_, result = op.Execute(context.Background(), NewRequest("", "", nil))
+			}
+
+			if got := op.(*CircuitBreakerOperator).breaker.stat(time.Now()).State; got != tt.wantState {
+				t.Errorf("state = %s, want %s", got, tt.wantState)
+			}
+			var apiErr *types.ApiError
+			switch {
+			case tt.wantCode != 0:
+				if !errors.As(result.Error, &apiErr) || apiErr.StatusCode != tt.wantCode {
+					t.Errorf("error = %v, want status %d", result.Error, tt.wantCode)
+				}
+			case result.Error != nil || result.Data != tt.wantData:
+				t.Errorf("result = %v, %v; want data %v", result.Data, result.Error, tt.wantData)
+			}
+		})
+	}
+}
+
+// breakerStateOf 返回 CircuitBreakers 中指定熔断器的状态
+func breakerStateOf(t *testing.T, name string) string {
+	t.Helper()
+	for _, stat := range CircuitBreakers() {
+		if stat.Name == name {
+			return stat.State
+		}
+	}
+	t.Fatalf("breaker %s not registered", name)
+	return ""
+}
+
+func TestCircuitBreakerSharedAcrossRoutes(t *testing.T) {
+	const breaker = "breaker_route_test"
+	var calls atomic.Int32
+	var failing atomic.Bool
+	registerTestOperator("breaker_route_test_dep", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		calls.Add(1)
+		if failing.Load() {
+			return ctx, &OperatorResult{Error: types.NewApiError("依赖不可用", "dependency down", http.StatusBadGateway)}
+		}
+		return ctx, &OperatorResult{Data: "ok"}
+	})
+
+	// 两个路由的节点通过同名熔断器共享状态
+	wrap := `"wrap": [{"name": "circuit_breaker", "config": {"breaker": "` + breaker + `", "failure_threshold": 2, "cooldown": "50ms"}}]`
+	s := newTestScheduler(t, `[
+		{"server_name": "/dep_a", "nodes": [{"name": "dep", "operator": "breaker_route_test_dep", `+wrap+`}]},
+		{"server_name": "/dep_b", "nodes": [{"name": "dep", "operator": "breaker_route_test_dep", `+wrap+`}]}
+	]`)
+
+	stateSeries := `scheduler_circuit_breaker_state{breaker="` + breaker + `"}`
+	transitionSeries := func(state string) string {
+		return `scheduler_circuit_breaker_transitions_total{breaker="` + breaker + `",state="` + state + `"}`
+	}
+	rejectionSeries := `scheduler_circuit_breaker_rejections_total{breaker="` + breaker + `"}`
+	openedBefore := metricValue(t, transitionSeries(BreakerOpen))
+	halfOpenBefore := metricValue(t, transitionSeries(BreakerHalfOpen))
+	closedBefore := metricValue(t, transitionSeries(BreakerClosed))
+	rejectedBefore := metricValue(t, rejectionSeries)
+
+	run := func(serverName string) int {
+		_, err := s.Run(context.Background(), serverName, ExecuteOptions{})
+		if err == nil {
+			return http.StatusOK
+		}
+		return statusOf(err)
+	}
+
+	failing.Store(true)
+	for i := 0; i < 2; i++ {
+		if got := run("/dep_a"); got != http.StatusBadGateway {
+			t.Fatalf("failure %d: status = %d, want %d", i, got, http.StatusBadGateway)
+		}
+	}
+	if got := breakerStateOf(t, breaker); got != BreakerOpen {
+		t.Fatalf("state after threshold = %s, want %s", got, BreakerOpen)
+	}
+	if got := metricValue(t, stateSeries); got != breakerStateValues[BreakerOpen] {
+		t.Errorf("%s = %v, want %v", stateSeries, got, breakerStateValues[BreakerOpen])
+	}
+
+	// 另一个路由被拒绝，内层算子不执行
+	callsBefore := calls.Load()
+	if got := run("/dep_b"); got != http.StatusServiceUnavailable {
+		t.Errorf("open breaker status = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if calls.Load() != callsBefore {
+		t.Error("open breaker executed the inner operator")
+	}
+	if got := metricValue(t, rejectionSeries) - rejectedBefore; got != 1 {
+		t.Errorf("%s increased by %v, want 1", rejectionSeries, got)
+	}
+
+	// 配置重载沿用熔断状态
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := breakerStateOf(t, breaker); got != BreakerOpen {
+		t.Errorf("state after reload = %s, want %s", got, BreakerOpen)
+	}
+
+	// 冷却后试探成功，恢复闭合
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+	if got := run("/dep_b"); got != http.StatusOK {
+		t.Fatalf("probe status = %d, want %d", got, http.StatusOK)
+	}
+	if got := breakerStateOf(t, breaker); got != BreakerClosed {
+		t.Errorf("state after probe = %s, want %s", got, BreakerClosed)
+	}
+	if got := metricValue(t, stateSeries); got != breakerStateValues[BreakerClosed] {
+		t.Errorf("%s = %v, want %v", stateSeries, got, breakerStateValues[BreakerClosed])
+	}
+	for state, before := range map[string]float64{BreakerOpen: openedBefore, BreakerHalfOpen: halfOpenBefore, BreakerClosed: closedBefore} {
+		if got := metricValue(t, transitionSeries(state)) - before; got != 1 {
+			t.Errorf("%s increased by %v, want 1", transitionSeries(state), got)
+		}
+	}
+}
+
+func TestCircuitBreakerCountsPanics(t *testing.T) {
+	const breaker = "breaker_panic_test"
+	registerTestOperator("breaker_panic_test_op", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		panic("dependency client crashed")
+	})
+
+	s := newTestScheduler(t, `[{"server_name": "/panic_dep", "nodes": [
+		{"name": "dep", "operator": "breaker_panic_test_op", "wrap": [{"name": "circuit_breaker", "config": {"breaker": "`+breaker+`", "failure_threshold": 1}}]}
+	]}]`)
+
+	if _, err := s.Run(context.Background(), "/panic_dep", ExecuteOptions{}); statusOf(err) != http.StatusInternalServerError {
+		t.Fatalf("panic status = %d, want %d", statusOf(err), http.StatusInternalServerError)
+	}
+	if got := breakerStateOf(t, breaker); got != BreakerOpen {
+		t.Errorf("state after panic = %s, want %s", got, BreakerOpen)
+	}
+}
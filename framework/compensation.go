@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// compensationTimeout 单个补偿操作的超时。补偿在路由失败后执行，此时请求上下文可能已超时或取消，
+// 因此补偿使用不随请求取消的上下文，并单独计时
+const compensationTimeout = 10 * time.Second
+
+// Compensator 可选接口：路由失败时撤销算子已生效的副作用（如删除已写入的记录）。
+// transactional 路由失败时由事务回滚撤销写入，不调用 Compensate。
+// data 为算子成功时返回的 OperatorResult.Data
+type Compensator interface {
+	Compensate(ctx context.Context, req *Request, data interface{}) error
+}
+
+// compensate 按执行顺序的逆序，对已成功执行且实现了 Compensator 的节点执行补偿，结果记录在 results 中
+func (s *Scheduler) compensate(ctx context.Context, exec *execution, results []ExecutionResult) {
+	for i := len(results) - 1; i >= 0; i-- {
+		result := &results[i]
+		if !result.Success || result.Skipped {
+			continue
+		}
+		compensator, ok := asOperator[Compensator](exec.plan.operators[result.OperatorName])
+		if !ok {
+			continue
+		}
+
+		span := exec.span.child("compensate " + result.OperatorName)
+		span.SetAttribute("route", exec.plan.serverName)
+		span.SetAttribute("node", result.OperatorName)
+
+		err := runCompensation(context.WithValue(ctx, spanKey{}, span), compensator, exec.opts.Request, result.Data)
+		span.End(err)
+
+		result.Compensated = err == nil
+		result.CompensationError = err
+		if err != nil {
+			log.Printf("Compensation for operator %s failed: %v", result.OperatorName, err)
+			compensations.Inc(exec.plan.serverName, result.OperatorName, "failed")
+		} else {
+			log.Printf("Compensated operator %s", result.OperatorName)
+			compensations.Inc(exec.plan.serverName, result.OperatorName, "succeeded")
+		}
+	}
+}
+
+// runCompensation 执行单个补偿，panic 视为补偿失败
+func runCompensation(ctx context.Context, compensator Compensator, req *Request, data interface{}) (err error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Compensation panicked: %v\n%s", recovered, debug.Stack())
+			err = fmt.Errorf("compensation panicked: %v", recovered)
+		}
+	}()
+	return compensator.Compensate(ctx, req, data)
+}
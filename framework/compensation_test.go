@@ -0,0 +1,116 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// compensatingOperator 记录补偿顺序的测试算子
+type compensatingOperator struct {
+	testOperator
+	compensate func(data interface{}) error
+}
+
+func (o *compensatingOperator) Compensate(ctx context.Context, req *Request, data interface{}) error {
+	return o.compensate(data)
+}
+
+func TestCompensation(t *testing.T) {
+	var mu sync.Mutex
+	var compensated []string
+	record := func(data interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		compensated = append(compensated, data.(string))
+		return nil
+	}
+	register := func(name string, compensate func(data interface{}) error) {
+		RegisterOperator(name, &compensatingOperator{
+			testOperator: testOperator{name: name, execute: dataOperator(name)},
+			compensate:   compensate,
+		})
+	}
+	register("comp_test_a", record)
+	register("comp_test_b", record)
+	register("comp_test_broken", func(data interface{}) error { return errors.New("undo failed") })
+	register("comp_test_panics", func(data interface{}) error { panic("boom") })
+	registerTestOperator("comp_test_plain", dataOperator("plain"))
+	registerTestOperator("comp_test_fail", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("失败", "failed", http.StatusBadGateway)}
+	})
+	SetTransactionBeginner(func(ctx context.Context) (Transaction, error) {
+		return recordingTransaction{mu: &sync.Mutex{}, events: new([]string)}, nil
+	})
+	defer SetTransactionBeginner(nil)
+
+	tests := []struct {
+		name            string
+		route           string
+		wantCompensated string            // 补偿执行顺序
+		wantResults     map[string]string // 节点 -> compensated / failed / 空（未补偿）
+	}{
+		{
+			name:            "reverse order of success",
+			route:           `{"server_name": "/c", "nodes": [{"name": "comp_test_a", "downstream": "comp_test_plain"}, {"name": "comp_test_plain", "downstream": "comp_test_b"}, {"name": "comp_test_b", "downstream": "comp_test_fail"}, {"name": "comp_test_fail"}]}`,
+			wantCompensated: "comp_test_b,comp_test_a",
+			wantResults:     map[string]string{"comp_test_a": "compensated", "comp_test_b": "compensated", "comp_test_plain": "", "comp_test_fail": ""},
+		},
+		{
+			name:            "failures and panics are recorded and do not stop compensation",
+			route:           `{"server_name": "/c", "nodes": [{"name": "comp_test_a", "downstream": "comp_test_broken"}, {"name": "comp_test_broken", "downstream": "comp_test_panics"}, {"name": "comp_test_panics", "downstream": "comp_test_fail"}, {"name": "comp_test_fail"}]}`,
+			wantCompensated: "comp_test_a",
+			wantResults:     map[string]string{"comp_test_a": "compensated", "comp_test_broken": "failed", "comp_test_panics": "failed"},
+		},
+		{
+			name:            "skipped nodes are not compensated",
+			route:           `{"server_name": "/c", "nodes": [{"name": "comp_test_a", "downstream": [{"node": "comp_test_b", "when": "false"}, "comp_test_fail"]}, {"name": "comp_test_b"}, {"name": "comp_test_fail"}]}`,
+			wantCompensated: "comp_test_a",
+			wantResults:     map[string]string{"comp_test_a": "compensated", "comp_test_b": ""},
+		},
+		{
+			name:            "successful routes are not compensated",
+			route:           `{"server_name": "/c", "nodes": [{"name": "comp_test_a", "downstream": "comp_test_b"}, {"name": "comp_test_b"}]}`,
+			wantCompensated: "",
+			wantResults:     map[string]string{"comp_test_a": "", "comp_test_b": ""},
+		},
+		{
+			name:            "transactional routes roll back instead",
+			route:           `{"server_name": "/c", "transactional": true, "nodes": [{"name": "comp_test_a", "downstream": "comp_test_fail"}, {"name": "comp_test_fail"}]}`,
+			wantCompensated: "",
+			wantResults:     map[string]string{"comp_test_a": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compensated = nil
+			s := newTestScheduler(t, "["+tt.route+"]")
+			output, _ := s.Run(context.Background(), "/c", ExecuteOptions{})
+
+			if got := strings.Join(compensated, ","); got != tt.wantCompensated {
+				t.Errorf("compensated = %q, want %q", got, tt.wantCompensated)
+			}
+			for _, result := range output.Results {
+				want, ok := tt.wantResults[result.OperatorName]
+				if !ok {
+					continue
+				}
+				got := ""
+				switch {
+				case result.Compensated:
+					got = "compensated"
+				case result.CompensationError != nil:
+					got = "failed"
+				}
+				if got != want {
+					t.Errorf("%s: compensation = %q, want %q", result.OperatorName, got, want)
+				}
+			}
+		})
+	}
+}
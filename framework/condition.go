@@ -0,0 +1,457 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Condition 边上的条件表达式，在加载配置时编译，执行时针对请求与上游结果求值。
+//
+// 支持的写法：
+//   - 变量: request.method / request.path / request.header.X-Name / request.query.name /
+//     request.param.id / data.<节点>.<字段路径>（字段名为上游结果的 JSON 字段名）
+//   - 字面量: 数字、"字符串" 或 '字符串'、true / false / null
+//   - 运算: == != > >= < <= && || ! 以及括号
+//
+// 单独的变量按真值判断：不存在、null、false、0 和空字符串为假。
+type Condition struct {
+	source string
+	root   conditionNode
+}
+
+// conditionEnv 条件求值时可访问的数据
+type conditionEnv struct {
+	ctx     context.Context
+	request *Request
+	data    func(nodeID string) (interface{}, bool)
+}
+
+type conditionNode interface {
+	eval(env *conditionEnv) interface{}
+}
+
+// CompileCondition 编译条件表达式
+func CompileCondition(source string) (*Condition, error) {
+	tokens, err := tokenizeCondition(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", source, err)
+	}
+
+	p := &conditionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", source, err)
+	}
+	return &Condition{source: source, root: root}, nil
+}
+
+func (c *Condition) String() string {
+	return c.source
+}
+
+// referencedNodes 返回表达式中 data.<节点> 引用的节点ID
+func (c *Condition) referencedNodes() []string {
+	var nodes []string
+	var walk func(n conditionNode)
+	walk = func(n conditionNode) {
+		switch v := n.(type) {
+		case *pathNode:
+			if len(v.parts) >= 2 && v.parts[0] == "data" {
+				nodes = append(nodes, v.parts[1])
+			}
+		case *unaryNode:
+			walk(v.operand)
+		case *binaryNode:
+			walk(v.left)
+			walk(v.right)
+		}
+	}
+	walk(c.root)
+	return nodes
+}
+
+func (c *Condition) evaluate(env *conditionEnv) bool {
+	return truthy(c.root.eval(env))
+}
+
+// ---- 词法分析 ----
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type conditionToken struct {
+	kind tokenKind
+	text string
+}
+
+func tokenizeCondition(source string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			var sb strings.Builder
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+				}
+				sb.WriteRune(runes[end])
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, conditionToken{kind: tokenString, text: sb.String()})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenNumber, text: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenIdent, text: string(runes[i:end])})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "(", ")"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenOperator, text: op})
+			i += len([]rune(op))
+		}
+	}
+	return tokens, nil
+}
+
+// isIdentRune 变量名允许 - 以支持 reminder-config、X-Request-Id 这类名称，
+// 允许 / 以支持 include 展开后的 prelude/auth 这类节点ID
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == '/'
+}
+
+// ---- 语法分析 ----
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) peekOperator(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOperator("||"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.peekOperator("&&"); !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *conditionParser) parseComparison() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.peekOperator("==", "!=", ">=", "<=", ">", "<")
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if _, ok := p.peekOperator("!"); ok {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (conditionNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return &literalNode{value: n}, nil
+	case tokenString:
+		return &literalNode{value: tok.text}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		parts := strings.Split(tok.text, ".")
+		if parts[0] != "request" && parts[0] != "data" {
+			return nil, fmt.Errorf("unknown variable %q, expected request.* or data.*", tok.text)
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("incomplete variable %q", tok.text)
+		}
+		return &pathNode{parts: parts}, nil
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.peekOperator(")"); !ok {
+				return nil, fmt.Errorf("missing )")
+			}
+			p.pos++
+			return inner, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// ---- 求值 ----
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(env *conditionEnv) interface{} {
+	return n.value
+}
+
+type unaryNode struct {
+	operand conditionNode
+}
+
+func (n *unaryNode) eval(env *conditionEnv) interface{} {
+	return !truthy(n.operand.eval(env))
+}
+
+type binaryNode struct {
+	op          string
+	left, right conditionNode
+}
+
+func (n *binaryNode) eval(env *conditionEnv) interface{} {
+	switch n.op {
+	case "&&":
+		return truthy(n.left.eval(env)) && truthy(n.right.eval(env))
+	case "||":
+		return truthy(n.left.eval(env)) || truthy(n.right.eval(env))
+	}
+
+	left, right := n.left.eval(env), n.right.eval(env)
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right)
+	case "!=":
+		return !valuesEqual(left, right)
+	}
+
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			return compareOrdered(n.op, l, r)
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compareOrdered(n.op, l, r)
+		}
+	}
+	return false
+}
+
+func compareOrdered[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	}
+	return false
+}
+
+type pathNode struct {
+	parts []string
+}
+
+func (n *pathNode) eval(env *conditionEnv) interface{} {
+	if n.parts[0] == "data" {
+		value, ok := env.data(n.parts[1])
+		if !ok {
+			return nil
+		}
+		for _, key := range n.parts[2:] {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = obj[key]
+		}
+		return value
+	}
+
+	r := env.request
+	switch n.parts[1] {
+	case "method":
+		if r != nil {
+			return r.Method
+		}
+	case "path":
+		if r != nil {
+			return r.Path
+		}
+	case "header":
+		if r != nil && len(n.parts) >= 3 {
+			return optionalString(r.Metadata.Get(strings.Join(n.parts[2:], ".")))
+		}
+	case "query":
+		if r != nil && len(n.parts) >= 3 {
+			return optionalString(r.Query.Get(strings.Join(n.parts[2:], ".")))
+		}
+	case "param":
+		if len(n.parts) >= 3 {
+			return optionalString(PathParam(env.ctx, strings.Join(n.parts[2:], ".")))
+		}
+	}
+	return nil
+}
+
+// optionalString 空字符串视为不存在
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case string:
+		return val != ""
+	default:
+		return true
+	}
+}
+
+func valuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64, string, bool:
+		return a == b
+	default:
+		// 对象和数组按 JSON 结构比较
+		aj, errA := json.Marshal(av)
+		bj, errB := json.Marshal(b)
+		return errA == nil && errB == nil && string(aj) == string(bj)
+	}
+}
+
+// toConditionValue 将上游结果转换为 JSON 结构（map/slice/float64/string/bool），字段名与响应一致
+func toConditionValue(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+	return value
+}
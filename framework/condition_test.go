@@ -0,0 +1,241 @@
+package framework
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCompileConditionErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{source: `request.method == "GET`, wantErr: "unterminated string"},
+		{source: `request.method = "GET"`, wantErr: `unexpected character '='`},
+		{source: `user.id == 1`, wantErr: `unknown variable "user.id"`},
+		{source: `data`, wantErr: `incomplete variable "data"`},
+		{source: `(data.a.ok`, wantErr: "missing )"},
+		{source: `data.a.ok &&`, wantErr: "unexpected end of expression"},
+		{source: `data.a.ok data.b.ok`, wantErr: `unexpected "data.b.ok"`},
+		{source: `data.a.n > )`, wantErr: `unexpected ")"`},
+		{source: `1.2.3 == 1`, wantErr: `invalid number "1.2.3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := CompileCondition(tt.source)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluate(t *testing.T) {
+	request := NewRequest("POST", "/records/7", nil)
+	request.Metadata.Set("X-Client", "ios")
+	request.Query = url.Values{"format": {"csv"}}
+	ctx := WithPathParams(context.Background(), map[string]string{"id": "7"})
+
+	outputs := map[string]interface{}{
+		"auth":            map[string]interface{}{"isValid": true, "user": map[string]interface{}{"id": "u1", "tags": []interface{}{"a", "b"}}},
+		"stats":           map[string]interface{}{"total": float64(1500), "percentage": float64(0), "label": ""},
+		"reminder-config": map[string]interface{}{"enabled": false},
+		"prelude/auth":    map[string]interface{}{"ok": true},
+	}
+	env := &conditionEnv{
+		ctx:     ctx,
+		request: request,
+		data: func(nodeID string) (interface{}, bool) {
+			value, ok := outputs[nodeID]
+			return value, ok
+		},
+	}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		// 请求变量
+		{source: `request.method == "POST"`, want: true},
+		{source: `request.method != 'POST'`, want: false},
+		{source: `request.path == "/records/7"`, want: true},
+		{source: `request.header.X-Client == "ios"`, want: true},
+		{source: `request.header.X-Missing`, want: false},
+		{source: `request.query.format == "csv"`, want: true},
+		{source: `request.param.id == "7"`, want: true},
+		{source: `request.param.missing == null`, want: true},
+
+		// 上游结果与字段路径
+		{source: `data.auth.isValid`, want: true},
+		{source: `data.auth.user.id == "u1"`, want: true},
+		{source: `data.auth.user.tags == data.auth.user.tags`, want: true},
+		{source: `data.auth.user.missing.deeper`, want: false},
+		{source: `data.unknown.field`, want: false},
+		{source: `data.reminder-config.enabled`, want: false},
+		{source: `data.prelude/auth.ok`, want: true},
+
+		// 真值判断
+		{source: `data.stats.percentage`, want: false},
+		{source: `data.stats.label`, want: false},
+		{source: `data.stats`, want: true},
+
+		// 比较
+		{source: `data.stats.total >= 1500`, want: true},
+		{source: `data.stats.total > 1500`, want: false},
+		{source: `data.stats.total < 2000.5`, want: true},
+		{source: `data.stats.total <= -1`, want: false},
+		{source: `request.method < "PUT"`, want: true},
+		{source: `data.stats.total > "1000"`, want: false},
+		{source: `data.stats.total == 1500`, want: true},
+		{source: `data.stats.total == "1500"`, want: false},
+
+		// 逻辑运算与优先级
+		{source: `!data.reminder-config.enabled`, want: true},
+		{source: `!!data.auth.isValid`, want: true},
+		{source: `data.auth.isValid && data.stats.total > 2000`, want: false},
+		{source: `data.auth.isValid || data.stats.total > 2000`, want: true},
+		{source: `false && true || true`, want: true},
+		{source: `false && (true || true)`, want: false},
+		{source: `true || false && false`, want: true},
+		{source: `!(data.stats.total > 2000) && request.method == "POST"`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			condition, err := CompileCondition(tt.source)
+			if err != nil {
+				t.Fatalf("CompileCondition: %v", err)
+			}
+			if got := condition.evaluate(env); got != tt.want {
+				t.Errorf("evaluate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionReferencedNodes(t *testing.T) {
+	condition, err := CompileCondition(`data.auth.ok && !(data.stats.total > 0 || request.method == "GET") && data.prelude/auth.ok`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(condition.referencedNodes(), ","); got != "auth,stats,prelude/auth" {
+		t.Errorf("referencedNodes = %q", got)
+	}
+}
+
+func TestConditionalEdgeConfigErrors(t *testing.T) {
+	registerTestOperator("edge_test_a", dataOperator("a"))
+	registerTestOperator("edge_test_b", dataOperator("b"))
+
+	tests := []struct {
+		name    string
+		nodes   string
+		wantErr string
+	}{
+		{
+			name:    "invalid expression",
+			nodes:   `[{"name": "edge_test_a", "downstream": [{"node": "edge_test_b", "when": "data.edge_test_a =="}]}, {"name": "edge_test_b"}]`,
+			wantErr: "edge edge_test_a -> edge_test_b: invalid condition",
+		},
+		{
+			name:    "unknown node",
+			nodes:   `[{"name": "edge_test_a", "downstream": [{"node": "edge_test_b", "when": "data.missing.ok"}]}, {"name": "edge_test_b"}]`,
+			wantErr: "condition references unknown node missing",
+		},
+		{
+			name: "conflicting conditions",
+			nodes: `[{"name": "edge_test_a", "downstream": [{"node": "edge_test_b", "when": "request.method == 'GET'"}]},
+			         {"name": "edge_test_b", "upstream": [{"node": "edge_test_a", "when": "request.method == 'POST'"}]}]`,
+			wantErr: "conflicting conditions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler()
+			err := s.LoadConfig(writeConfig(t, `[{"server_name": "/edge", "nodes": `+tt.nodes+`}]`))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionalEdges(t *testing.T) {
+	registerTestOperator("edge_test_flag", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Data: map[string]bool{"on": req.Query.Get("flag") == "on"}}
+	})
+	registerTestOperator("edge_test_leaf", dataOperator("leaf"))
+
+	tests := []struct {
+		name        string
+		nodes       string
+		flag        string
+		wantSkipped string
+	}{
+		{
+			name:  "condition on upstream data holds",
+			nodes: `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "leaf", "when": "data.flag.on"}]}, {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag:  "on",
+		},
+		{
+			name:        "condition on upstream data fails",
+			nodes:       `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "leaf", "when": "data.flag.on"}]}, {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag:        "off",
+			wantSkipped: "leaf",
+		},
+		{
+			// 任一已执行上游的边成立即可激活
+			name: "unconditional edge activates despite a failed condition",
+			nodes: `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "leaf", "when": "data.flag.on"}, "other"]},
+			         {"name": "other", "operator": "edge_test_leaf", "downstream": "leaf"},
+			         {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag: "off",
+		},
+		{
+			// 被跳过节点的下游即使边上没有条件也被跳过
+			name: "skip propagates downstream",
+			nodes: `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "mid", "when": "data.flag.on"}]},
+			         {"name": "mid", "operator": "edge_test_leaf", "downstream": "leaf"},
+			         {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag:        "off",
+			wantSkipped: "mid,leaf",
+		},
+		{
+			// 引用被跳过节点的条件按不存在处理
+			name: "condition on a skipped node is false",
+			nodes: `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "mid", "when": "data.flag.on"}, "other"]},
+			         {"name": "mid", "operator": "edge_test_leaf"},
+			         {"name": "other", "operator": "edge_test_leaf", "downstream": [{"node": "leaf", "when": "data.mid == 'leaf'"}]},
+			         {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag:        "off",
+			wantSkipped: "mid,leaf",
+		},
+		{
+			name:  "condition on the request",
+			nodes: `[{"name": "flag", "operator": "edge_test_flag", "downstream": [{"node": "leaf", "when": "request.query.flag == 'on' && request.method == 'GET'"}]}, {"name": "leaf", "operator": "edge_test_leaf"}]`,
+			flag:  "on",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, `[{"server_name": "/edge", "nodes": `+tt.nodes+`}]`)
+			request := NewRequest("GET", "/edge", nil)
+			request.Query.Set("flag", tt.flag)
+
+			output, err := s.Run(context.Background(), "/edge", ExecuteOptions{Request: request})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			var skipped []string
+			for _, result := range output.Results {
+				if result.Skipped {
+					skipped = append(skipped, result.OperatorName)
+				}
+			}
+			if got := strings.Join(skipped, ","); got != tt.wantSkipped {
+				t.Errorf("skipped = %q, want %q", got, tt.wantSkipped)
+			}
+		})
+	}
+}
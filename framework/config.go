@@ -0,0 +1,244 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RouteConfig 单个路由的调度配置
+//
+// 支持两种写法：
+//   - dependencies: 旧格式，算子 -> 下游算子，值可以是字符串或字符串数组
+//   - nodes: 节点列表，每个节点可声明多个上游/下游，用于扇出/扇入（菱形）图
+type RouteConfig struct {
+	ServerName     string            `json:"server_name"`
+	Method         string            `json:"method,omitempty"`          // 为空时匹配任意方法
+	Path           string            `json:"path,omitempty"`            // 路径模式，如 /records/{id}，为空时使用 server_name
+	Aliases        []RouteAlias      `json:"aliases,omitempty"`         // 附加的入口，如改路径后保留的旧路径
+	Timeout        Duration          `json:"timeout,omitempty"`         // 整个路由的超时，如 "3s"
+	Mode           string            `json:"mode,omitempty"`            // sequential（默认）或 parallel，parallel 时同一层级的节点并发执行
+	MaxConcurrency int               `json:"max_concurrency,omitempty"` // parallel 模式下单个请求同时执行的节点上限，0 表示不限制
+	Transactional  bool              `json:"transactional,omitempty"`   // 所有节点共享一个数据库事务，全部成功时提交，否则回滚
+	MaxBodyBytes   int64             `json:"max_body_bytes,omitempty"`  // 请求体大小上限，0 时使用 DefaultMaxBodyBytes
+	Dependencies   map[string]Edges  `json:"dependencies,omitempty"`
+	Nodes          []NodeConfig      `json:"nodes,omitempty"`
+	Response       *ResponseConfig   `json:"response,omitempty"`
+	Quarantine     *QuarantinePolicy `json:"quarantine,omitempty"` // 反复 panic 的算子暂停执行
+}
+
+// RouteAlias 路由的附加入口，匹配后执行同一路由；method 为空时沿用路由的 method
+type RouteAlias struct {
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+}
+
+// NodeConfig 图中的单个节点
+//
+// name 是节点在图中的唯一ID。算子来源二选一：
+//   - factory: 使用已注册的算子工厂按 config 创建独立实例，同一工厂可在图中多次使用
+//   - operator: 使用全局注册表中的单例算子，为空时使用 name
+//
+// wrap 中的装饰器依次包装上述算子，如 [{"name": "cache", "config": {"ttl": "60s"}}]
+//
+// include 不为空时节点不对应算子，加载时展开为被引用的管道或路由的全部节点
+type NodeConfig struct {
+	Name       string                 `json:"name"`
+	Include    string                 `json:"include,omitempty"` // 管道名或路由的 server_name
+	Operator   string                 `json:"operator,omitempty"`
+	Factory    string                 `json:"factory,omitempty"`
+	Config     map[string]interface{} `json:"config,omitempty"`
+	Timeout    Duration               `json:"timeout,omitempty"` // 单个算子的超时，如 "500ms"，重试时每次尝试单独计时
+	Retry      *RetryPolicy           `json:"retry,omitempty"`
+	Wrap       []DecoratorConfig      `json:"wrap,omitempty"` // 装饰器列表，第一个最靠近算子
+	Upstream   Edges                  `json:"upstream,omitempty"`
+	Downstream Edges                  `json:"downstream,omitempty"`
+}
+
+// Duration 支持 "500ms"、"3s" 等写法的时长
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string like \"500ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	if parsed < 0 {
+		return fmt.Errorf("duration %s must not be negative", text)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Edge 一条边，When 非空时为条件边，只有条件成立时下游节点才会被激活
+type Edge struct {
+	Node string `json:"node"`
+	When string `json:"when,omitempty"`
+}
+
+// Edges 边列表，兼容 "" / "op" / ["op1", {"node": "op2", "when": "..."}] 等写法
+type Edges []Edge
+
+func (e *Edges) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single == "" {
+			*e = nil
+		} else {
+			*e = Edges{{Node: single}}
+		}
+		return nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("edges must be a string or an array: %w", err)
+	}
+
+	*e = nil
+	for _, item := range list {
+		var edge Edge
+		if err := json.Unmarshal(item, &edge.Node); err != nil {
+			if err := json.Unmarshal(item, &edge); err != nil {
+				return fmt.Errorf("edge must be a string or an object with node/when: %w", err)
+			}
+		}
+		if edge.Node != "" { // 忽略空字符串依赖
+			*e = append(*e, edge)
+		}
+	}
+	return nil
+}
+
+// edgeKey 有向边 from -> to
+type edgeKey struct {
+	from, to string
+}
+
+// routeGraph 路由配置转换后的图结构
+type routeGraph struct {
+	downstream map[string][]string // 算子 -> 下游算子列表的邻接表
+	conditions map[edgeKey]string  // 条件边 -> 条件表达式
+}
+
+// routePath 路由匹配使用的路径模式
+func (c *RouteConfig) routePath() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return c.ServerName
+}
+
+// routeAliases 附加入口，method 已按路由补全
+func (c *RouteConfig) routeAliases() []RouteAlias {
+	aliases := make([]RouteAlias, 0, len(c.Aliases))
+	for _, alias := range c.Aliases {
+		if alias.Method == "" {
+			alias.Method = c.Method
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// addRoutes 将路由的主入口与附加入口注册到路由器
+func (c *RouteConfig) addRoutes(router *Router) error {
+	if err := router.Add(c.Method, c.routePath(), c.ServerName); err != nil {
+		return err
+	}
+	for _, alias := range c.routeAliases() {
+		if err := router.Add(alias.Method, alias.Path, c.ServerName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildGraph 将路由配置统一转换为邻接表，并收集条件边
+func (c *RouteConfig) buildGraph() (*routeGraph, error) {
+	graph := &routeGraph{
+		downstream: make(map[string][]string),
+		conditions: make(map[edgeKey]string),
+	}
+	addNode := func(name string) {
+		if _, exists := graph.downstream[name]; !exists {
+			graph.downstream[name] = nil
+		}
+	}
+	declared := make(map[edgeKey]string)
+	addEdge := func(from, to, when string) error {
+		addNode(from)
+		addNode(to)
+
+		// 同一条边可以在上游和下游两侧重复声明，但条件必须一致
+		key := edgeKey{from: from, to: to}
+		if existing, ok := declared[key]; ok {
+			if existing != when {
+				return fmt.Errorf("edge %s -> %s declared with conflicting conditions", from, to)
+			}
+			return nil
+		}
+		declared[key] = when
+
+		if when != "" {
+			graph.conditions[key] = when
+		}
+		graph.downstream[from] = append(graph.downstream[from], to)
+		return nil
+	}
+
+	for op, downstream := range c.Dependencies {
+		addNode(op)
+		for _, dep := range downstream {
+			if err := addEdge(op, dep.Node, dep.When); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(c.Nodes))
+	for _, node := range c.Nodes {
+		if node.Name == "" {
+			return nil, fmt.Errorf("node name is required")
+		}
+		if seen[node.Name] {
+			return nil, fmt.Errorf("duplicate node %s", node.Name)
+		}
+		seen[node.Name] = true
+		addNode(node.Name)
+		for _, up := range node.Upstream {
+			if err := addEdge(up.Node, node.Name, up.When); err != nil {
+				return nil, err
+			}
+		}
+		for _, down := range node.Downstream {
+			if err := addEdge(node.Name, down.Node, down.When); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// nodeConfigs 返回节点ID -> 节点配置，旧格式的节点只有名称
+func (c *RouteConfig) nodeConfigs(graph *routeGraph) map[string]NodeConfig {
+	nodes := make(map[string]NodeConfig, len(graph.downstream))
+	for name := range graph.downstream {
+		nodes[name] = NodeConfig{Name: name}
+	}
+	for _, node := range c.Nodes {
+		nodes[node.Name] = node
+	}
+	return nodes
+}
@@ -0,0 +1,38 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// StatusClientClosedRequest 客户端在响应前断开连接（沿用 nginx 的 499 约定）
+const StatusClientClosedRequest = 499
+
+// valuesContext 取消与截止时间来自 parent，值来自 values
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c *valuesContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// withValuesFrom 返回一个继承 parent 取消信号、但读取 values 中值的上下文
+func withValuesFrom(parent, values context.Context) context.Context {
+	if parent == values {
+		return parent
+	}
+	return &valuesContext{Context: parent, values: values}
+}
+
+// contextError 将上下文结束原因转换为算子错误：超时返回504，请求取消返回499
+func contextError(nodeID string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return types.NewApiError("请求超时", "Operator "+nodeID+" timed out", http.StatusGatewayTimeout)
+	}
+	return types.NewApiError("请求已取消", "Request canceled before operator "+nodeID+" finished", StatusClientClosedRequest)
+}
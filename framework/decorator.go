@@ -0,0 +1,102 @@
+package framework
+
+import (
+	"fmt"
+	"sync"
+)
+
+// OperatorDecorator 算子装饰器：在不修改算子的前提下为节点增加缓存、缓存失效等横切能力
+type OperatorDecorator interface {
+	Name() string
+	Wrap(op Operator, config map[string]interface{}) (Operator, error)
+}
+
+// WrappedOperator 装饰器返回的算子应实现 Unwrap，
+// 调度器据此识别内层算子实现的可选接口（如 IdempotentOperator、Compensator）
+type WrappedOperator interface {
+	Operator
+	Unwrap() Operator
+}
+
+// DecoratorConfig 节点上的单个装饰器配置
+type DecoratorConfig struct {
+	Name   string                 `json:"name"`
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// BaseOperatorDecorator 基础算子装饰器
+type BaseOperatorDecorator struct {
+	name    string
+	wrapper func(op Operator, config map[string]interface{}) (Operator, error)
+}
+
+var (
+	operatorDecorators = make(map[string]OperatorDecorator)
+	decoratorMutex     sync.RWMutex
+)
+
+// RegisterOperatorDecorator 注册算子装饰器
+func RegisterOperatorDecorator(name string, decorator OperatorDecorator) {
+	decoratorMutex.Lock()
+	defer decoratorMutex.Unlock()
+	operatorDecorators[name] = decorator
+}
+
+// lookupOperatorDecorator 按名称查找算子装饰器
+func lookupOperatorDecorator(name string) (OperatorDecorator, bool) {
+	decoratorMutex.RLock()
+	defer decoratorMutex.RUnlock()
+
+	decorator, exists := operatorDecorators[name]
+	return decorator, exists
+}
+
+// NewOperatorDecorator 创建新的算子装饰器
+func NewOperatorDecorator(name string, wrapper func(op Operator, config map[string]interface{}) (Operator, error)) OperatorDecorator {
+	return &BaseOperatorDecorator{
+		name:    name,
+		wrapper: wrapper,
+	}
+}
+
+// Name 返回装饰器名称
+func (d *BaseOperatorDecorator) Name() string {
+	return d.name
+}
+
+// Wrap 包装算子
+func (d *BaseOperatorDecorator) Wrap(op Operator, config map[string]interface{}) (Operator, error) {
+	return d.wrapper(op, config)
+}
+
+// decorate 按配置顺序包装节点的算子，列表中第一个装饰器最靠近算子
+func decorate(op Operator, node NodeConfig) (Operator, error) {
+	for _, wrap := range node.Wrap {
+		decorator, exists := lookupOperatorDecorator(wrap.Name)
+		if !exists {
+			return nil, fmt.Errorf("operator decorator %s not found for node %s", wrap.Name, node.Name)
+		}
+		wrapped, err := decorator.Wrap(op, wrap.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap node %s with %s: %w", node.Name, wrap.Name, err)
+		}
+		op = wrapped
+	}
+	return op, nil
+}
+
+// asOperator 沿装饰器链查找实现了接口 T 的算子，外层优先
+func asOperator[T any](op Operator) (T, bool) {
+	for op != nil {
+		if target, ok := op.(T); ok {
+			return target, true
+		}
+		wrapped, ok := op.(WrappedOperator)
+		if !ok {
+			break
+		}
+		op = wrapped.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
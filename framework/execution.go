@@ -0,0 +1,263 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ExecutionOutput 路由执行的完整输出
+type ExecutionOutput struct {
+	Results  []ExecutionResult
+	Body     interface{} // 按路由 response 配置组装的响应数据
+	Response *Response   // 合并后的HTTP响应控制，没有算子设置时为 nil
+	TraceID  string
+	Timeline []TimelineEntry // 按执行顺序排列的节点耗时
+}
+
+// TimelineEntry 执行时间线中的单个节点，时间以毫秒计、相对请求开始
+type TimelineEntry struct {
+	Node     string  `json:"node"`
+	Operator string  `json:"operator"`
+	Level    int     `json:"level"`
+	StartMs  float64 `json:"startMs"`
+	Duration float64 `json:"durationMs"`
+	Attempts int     `json:"attempts,omitempty"`
+	Skipped  bool    `json:"skipped,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// ResponseConfig 路由响应数据来源，from 与 compose 二选一：
+//   - from: 使用指定节点的结果数据
+//   - compose: 组合多个节点的结果，键为响应字段名，值为节点ID；未执行的节点对应字段为 null
+type ResponseConfig struct {
+	From    string            `json:"from,omitempty"`
+	Compose map[string]string `json:"compose,omitempty"`
+}
+
+func (c *ResponseConfig) validate(graph *routeGraph) error {
+	if c == nil {
+		return nil
+	}
+	if (c.From == "") == (len(c.Compose) == 0) {
+		return fmt.Errorf("response requires exactly one of from or compose")
+	}
+
+	nodes := []string{c.From}
+	if c.From == "" {
+		nodes = nodes[:0]
+		for _, nodeID := range c.Compose {
+			nodes = append(nodes, nodeID)
+		}
+	}
+	for _, nodeID := range nodes {
+		if _, exists := graph.downstream[nodeID]; !exists {
+			return fmt.Errorf("response references unknown node %s", nodeID)
+		}
+	}
+	return nil
+}
+
+type executionKey struct{}
+
+func withExecution(ctx context.Context, exec *execution) context.Context {
+	return context.WithValue(ctx, executionKey{}, exec)
+}
+
+// Output 按节点ID读取已成功执行的上游节点的结果数据，
+// 节点未执行、被跳过或数据类型与 T 不一致时返回 false
+func Output[T any](ctx context.Context, nodeID string) (T, bool) {
+	var zero T
+	exec, _ := ctx.Value(executionKey{}).(*execution)
+	if exec == nil {
+		return zero, false
+	}
+
+	exec.mu.RLock()
+	defer exec.mu.RUnlock()
+
+	if !exec.ran[nodeID] {
+		return zero, false
+	}
+	value, ok := exec.outputs[nodeID].(T)
+	return value, ok
+}
+
+// RouteName 返回当前执行的路由 server_name，不在路由执行中时返回空字符串
+func RouteName(ctx context.Context) string {
+	exec, _ := ctx.Value(executionKey{}).(*execution)
+	if exec == nil {
+		return ""
+	}
+	return exec.plan.serverName
+}
+
+// execution 单次请求的执行状态
+type execution struct {
+	mu      sync.RWMutex // 保护 ran/outputs，算子可在执行过程中读取上游结果
+	plan    *executionPlan
+	opts    ExecuteOptions
+	ran     map[string]bool        // 已成功执行的节点
+	outputs map[string]interface{} // 节点ID -> 结果数据
+	values  map[string]interface{} // 条件求值使用的结果 JSON 结构缓存
+	span    *Span                  // 请求的根 span
+	spans   map[string]*Span       // 节点ID -> 节点 span
+}
+
+func newExecution(plan *executionPlan, opts ExecuteOptions) *execution {
+	return &execution{
+		plan:    plan,
+		opts:    opts,
+		ran:     make(map[string]bool),
+		outputs: make(map[string]interface{}),
+		values:  make(map[string]interface{}),
+		spans:   make(map[string]*Span),
+	}
+}
+
+// startNodeSpan 在请求根 span 下为节点创建 span
+func (e *execution) startNodeSpan(nodeID string) *Span {
+	span := e.span.child("operator " + nodeID)
+	span.SetAttribute("route", e.plan.serverName)
+	span.SetAttribute("node", nodeID)
+	span.SetAttribute("operator", e.plan.operatorName(nodeID))
+	span.SetAttribute("level", e.plan.nodeLevels[nodeID])
+
+	e.mu.Lock()
+	e.spans[nodeID] = span
+	e.mu.Unlock()
+	return span
+}
+
+// timeline 按结果顺序生成执行时间线
+func (e *execution) timeline(results []ExecutionResult) []TimelineEntry {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	entries := make([]TimelineEntry, 0, len(results))
+	for _, result := range results {
+		entry := TimelineEntry{
+			Node:     result.OperatorName,
+			Operator: e.plan.operatorName(result.OperatorName),
+			Level:    e.plan.nodeLevels[result.OperatorName],
+			Attempts: result.Attempts,
+			Skipped:  result.Skipped,
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		if span := e.spans[result.OperatorName]; span != nil {
+			entry.StartMs = milliseconds(span.StartTime.Sub(e.span.StartTime))
+			entry.Duration = milliseconds(span.Duration())
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// record 记录节点的执行结果，供下游条件边求值
+func (e *execution) record(result ExecutionResult) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if result.Success && !result.Skipped {
+		e.ran[result.OperatorName] = true
+		e.outputs[result.OperatorName] = result.Data
+	}
+}
+
+// shouldRun 判断节点是否被激活：根节点总是执行；其余节点至少有一条入边激活，
+// 即上游已成功执行且边上的条件成立（无条件视为成立）
+func (e *execution) shouldRun(ctx context.Context, nodeID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	upstream := e.plan.upstream[nodeID]
+	if len(upstream) == 0 {
+		return true
+	}
+
+	env := &conditionEnv{ctx: ctx, request: e.opts.Request, data: e.conditionData}
+	for _, up := range upstream {
+		if !e.ran[up] {
+			continue
+		}
+		cond := e.plan.conditions[edgeKey{from: up, to: nodeID}]
+		if cond == nil || cond.evaluate(env) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *execution) conditionData(nodeID string) (interface{}, bool) {
+	if !e.ran[nodeID] {
+		return nil, false
+	}
+	if value, ok := e.values[nodeID]; ok {
+		return value, true
+	}
+	value := toConditionValue(e.outputs[nodeID])
+	e.values[nodeID] = value
+	return value, true
+}
+
+func skippedResult(nodeID string) ExecutionResult {
+	return ExecutionResult{OperatorName: nodeID, Skipped: true}
+}
+
+// responseControl 返回提供响应数据的节点的响应控制，与 buildResponse 选择的节点一致；没有时返回 nil
+func (e *execution) responseControl(results []ExecutionResult) *Response {
+	var source *ExecutionResult
+	switch response := e.plan.response; {
+	case response == nil:
+		for i := len(results) - 1; i >= 0; i-- {
+			if !results[i].Skipped {
+				source = &results[i]
+				break
+			}
+		}
+	case response.From != "":
+		for i := range results {
+			if results[i].OperatorName == response.From {
+				source = &results[i]
+			}
+		}
+	}
+
+	if source == nil || !source.Success || source.Response == nil {
+		return nil
+	}
+	return source.Response.clone()
+}
+
+// buildResponse 按路由的 response 配置组装响应数据；
+// 未配置时使用最后一个实际执行的节点（条件分支未激活的节点会被跳过）
+func (e *execution) buildResponse(results []ExecutionResult) interface{} {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	response := e.plan.response
+	switch {
+	case response == nil:
+		for i := len(results) - 1; i >= 0; i-- {
+			if !results[i].Skipped {
+				return results[i].Data
+			}
+		}
+		return nil
+	case response.From != "":
+		return e.outputs[response.From]
+	default:
+		body := make(map[string]interface{}, len(response.Compose))
+		for field, nodeID := range response.Compose {
+			body[field] = e.outputs[nodeID]
+		}
+		return body
+	}
+}
@@ -0,0 +1,414 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 诊断级别
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic 配置检查发现的单个问题
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Route    string `json:"route,omitempty"`
+	Node     string `json:"node,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	location := d.Route
+	if d.Node != "" {
+		location += " node " + d.Node
+	}
+	if location == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, location, d.Message)
+}
+
+// PlanEdge 执行图中的一条边
+type PlanEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	When string `json:"when,omitempty"`
+}
+
+// RoutePlan 单个路由的执行图与按层级分组的执行顺序
+type RoutePlan struct {
+	ServerName string            `json:"serverName"`
+	Method     string            `json:"method,omitempty"`
+	Path       string            `json:"path"`
+	Aliases    []RouteAlias      `json:"aliases,omitempty"`
+	Mode       string            `json:"mode"`
+	Operators  map[string]string `json:"operators"`        // 节点ID -> 算子名称，无法解析的节点为空
+	Levels     [][]string        `json:"levels,omitempty"` // 图中有环时为空
+	Edges      []PlanEdge        `json:"edges"`
+	Includes   map[string]string `json:"includes,omitempty"` // 展开的 include 节点ID -> 管道名或路由名
+}
+
+// PlanReport 配置检查结果
+type PlanReport struct {
+	Routes      []RoutePlan  `json:"routes"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// HasErrors 是否存在会导致配置加载失败的问题
+func (r *PlanReport) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintConfig 按已注册的算子检查配置文件，收集全部问题而不是遇到第一个错误就返回。
+// 只有文件无法读取或解析时才返回错误。
+func (s *Scheduler) LintConfig(configPath string) (*PlanReport, error) {
+	file, err := readConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PlanReport{}
+	router := NewRouter()
+	seen := make(map[string]bool, len(file.Routes))
+	included := make(map[string]bool)
+	for _, config := range file.Routes {
+		if seen[config.ServerName] {
+			report.addError(config.ServerName, "", "duplicate server_name")
+			continue
+		}
+		seen[config.ServerName] = true
+
+		if err := config.addRoutes(router); err != nil {
+			report.addError(config.ServerName, "", err.Error())
+		}
+
+		// 后续检查与执行计划都基于展开后的图
+		expanded, includes, err := file.expandRoute(config)
+		for _, target := range includes {
+			included[target] = true
+		}
+		if err != nil {
+			report.addError(config.ServerName, "", err.Error())
+			continue
+		}
+		if plan := s.lintRoute(expanded, report); plan != nil {
+			plan.Includes = includes
+			report.Routes = append(report.Routes, *plan)
+		}
+	}
+
+	pipelines := make([]string, 0, len(file.Pipelines))
+	for name := range file.Pipelines {
+		if !included[name] {
+			pipelines = append(pipelines, name)
+		}
+	}
+	sort.Strings(pipelines)
+	for _, name := range pipelines {
+		report.addWarning("", "", fmt.Sprintf("pipeline %s is not included by any route", name))
+	}
+	return report, nil
+}
+
+// lintRoute 检查单个路由，图无法构建时返回 nil
+func (s *Scheduler) lintRoute(config RouteConfig, report *PlanReport) *RoutePlan {
+	route := config.ServerName
+	graph, err := config.buildGraph()
+	if err != nil {
+		report.addError(route, "", err.Error())
+		return nil
+	}
+
+	plan := &RoutePlan{
+		ServerName: route,
+		Method:     config.Method,
+		Path:       config.routePath(),
+		Aliases:    config.routeAliases(),
+		Mode:       config.Mode,
+		Operators:  make(map[string]string, len(graph.downstream)),
+	}
+	if plan.Mode == "" {
+		plan.Mode = ModeSequential
+	}
+	before := len(report.Diagnostics)
+
+	nodes := config.nodeConfigs(graph)
+	for _, nodeID := range sortedNodes(graph.downstream) {
+		op, err := s.resolveOperator(route, nodes[nodeID])
+		if err != nil {
+			report.addError(route, nodeID, err.Error())
+			plan.Operators[nodeID] = ""
+			continue
+		}
+		plan.Operators[nodeID] = op.Name()
+
+		if policy := nodes[nodeID].Retry; policy != nil {
+			if err := policy.validate(nodeID, op); err != nil {
+				report.addError(route, nodeID, err.Error())
+			}
+		}
+	}
+
+	for _, from := range sortedNodes(graph.downstream) {
+		for _, to := range graph.downstream[from] {
+			plan.Edges = append(plan.Edges, PlanEdge{From: from, To: to, When: graph.conditions[edgeKey{from: from, to: to}]})
+		}
+	}
+	sort.Slice(plan.Edges, func(i, j int) bool {
+		if plan.Edges[i].From != plan.Edges[j].From {
+			return plan.Edges[i].From < plan.Edges[j].From
+		}
+		return plan.Edges[i].To < plan.Edges[j].To
+	})
+
+	if levels, err := s.topologicalSort(graph.downstream); err == nil {
+		plan.Levels = levels
+	} else {
+		report.addError(route, "", fmt.Sprintf("cycle detected, nodes unreachable: %s", strings.Join(cyclicNodes(graph.downstream), ", ")))
+	}
+
+	lintConditions(route, graph, report)
+	lintIsolatedNodes(route, graph, report)
+
+	if err := config.validateMode(); err != nil {
+		report.addError(route, "", err.Error())
+	}
+	if err := config.validateTransactional(); err != nil {
+		report.addError(route, "", err.Error())
+	}
+	if err := config.validateBodyLimit(); err != nil {
+		report.addError(route, "", err.Error())
+	}
+	if err := config.Quarantine.validate(); err != nil {
+		report.addError(route, "", err.Error())
+	}
+	if err := config.Response.validate(graph); err != nil {
+		report.addError(route, "", err.Error())
+	}
+
+	// 兜底：上面的检查没有发现问题时，用加载配置的同一路径再编译一次
+	if len(report.Diagnostics) == before {
+		if _, err := s.compileRoute(config); err != nil {
+			report.addError(route, "", err.Error())
+		}
+	}
+	return plan
+}
+
+// lintConditions 检查条件表达式：语法错误、引用未知节点，以及引用了不在边的上游的节点（求值时总是拿不到数据）
+func lintConditions(route string, graph *routeGraph, report *PlanReport) {
+	edges := make([]edgeKey, 0, len(graph.conditions))
+	for edge := range graph.conditions {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+
+	for _, edge := range edges {
+		cond, err := CompileCondition(graph.conditions[edge])
+		if err != nil {
+			report.addError(route, edge.to, fmt.Sprintf("edge %s -> %s: %v", edge.from, edge.to, err))
+			continue
+		}
+		ancestors := ancestorsOf(graph.downstream, edge.from)
+		for _, ref := range cond.referencedNodes() {
+			if _, exists := graph.downstream[ref]; !exists {
+				report.addError(route, edge.to, fmt.Sprintf("edge %s -> %s: condition references unknown node %s", edge.from, edge.to, ref))
+				continue
+			}
+			if ref != edge.from && !ancestors[ref] {
+				report.addWarning(route, edge.to, fmt.Sprintf("edge %s -> %s: condition references %s, which never runs before this edge", edge.from, edge.to, ref))
+			}
+		}
+	}
+}
+
+// lintIsolatedNodes 多节点的图中没有任何边的节点通常是拼写错误
+func lintIsolatedNodes(route string, graph *routeGraph, report *PlanReport) {
+	if len(graph.downstream) < 2 {
+		return
+	}
+	connected := make(map[string]bool, len(graph.downstream))
+	for from, tos := range graph.downstream {
+		for _, to := range tos {
+			connected[from] = true
+			connected[to] = true
+		}
+	}
+	for _, nodeID := range sortedNodes(graph.downstream) {
+		if !connected[nodeID] {
+			report.addWarning(route, nodeID, "node has no edges and runs as an independent root")
+		}
+	}
+}
+
+// ancestorsOf 返回 nodeID 的全部上游节点
+func ancestorsOf(downstream map[string][]string, nodeID string) map[string]bool {
+	upstream := make(map[string][]string, len(downstream))
+	for from, tos := range downstream {
+		for _, to := range tos {
+			upstream[to] = append(upstream[to], from)
+		}
+	}
+
+	ancestors := make(map[string]bool)
+	stack := []string{nodeID}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, up := range upstream[current] {
+			if !ancestors[up] {
+				ancestors[up] = true
+				stack = append(stack, up)
+			}
+		}
+	}
+	return ancestors
+}
+
+// cyclicNodes 拓扑排序无法处理的节点：环上的节点及其下游
+func cyclicNodes(downstream map[string][]string) []string {
+	inDegree := make(map[string]int, len(downstream))
+	for from := range downstream {
+		inDegree[from] += 0
+		for _, to := range downstream[from] {
+			inDegree[to]++
+		}
+	}
+
+	var queue []string
+	for nodeID, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, nodeID)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		delete(inDegree, current)
+		for _, to := range downstream[current] {
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	remaining := make([]string, 0, len(inDegree))
+	for nodeID := range inDegree {
+		remaining = append(remaining, nodeID)
+	}
+	sort.Strings(remaining)
+	return remaining
+}
+
+func sortedNodes(downstream map[string][]string) []string {
+	nodes := make([]string, 0, len(downstream))
+	for nodeID := range downstream {
+		nodes = append(nodes, nodeID)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (r *PlanReport) addError(route, node, message string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{Severity: SeverityError, Route: route, Node: node, Message: message})
+}
+
+func (r *PlanReport) addWarning(route, node, message string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{Severity: SeverityWarning, Route: route, Node: node, Message: message})
+}
+
+// DOT 以 Graphviz DOT 格式导出全部路由，每个路由一个子图
+func (r *PlanReport) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph scheduler {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for i, route := range r.Routes {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%q;\n", route.title())
+		for _, nodeID := range route.nodeIDs() {
+			fmt.Fprintf(&b, "    %q [label=%q];\n", route.ServerName+"/"+nodeID, route.nodeLabel(nodeID))
+		}
+		for _, edge := range route.Edges {
+			from, to := route.ServerName+"/"+edge.From, route.ServerName+"/"+edge.To
+			if edge.When != "" {
+				fmt.Fprintf(&b, "    %q -> %q [label=%q, style=dashed];\n", from, to, edge.When)
+			} else {
+				fmt.Fprintf(&b, "    %q -> %q;\n", from, to)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid 以 Mermaid flowchart 格式导出全部路由，每个路由一个子图
+func (r *PlanReport) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, route := range r.Routes {
+		ids := make(map[string]string, len(route.Operators))
+		fmt.Fprintf(&b, "  subgraph r%d[\"%s\"]\n", i, mermaidText(route.title()))
+		for j, nodeID := range route.nodeIDs() {
+			ids[nodeID] = fmt.Sprintf("r%dn%d", i, j)
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[nodeID], mermaidText(route.nodeLabel(nodeID)))
+		}
+		for _, edge := range route.Edges {
+			if edge.When != "" {
+				fmt.Fprintf(&b, "    %s -.->|\"%s\"| %s\n", ids[edge.From], mermaidText(edge.When), ids[edge.To])
+			} else {
+				fmt.Fprintf(&b, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+			}
+		}
+		b.WriteString("  end\n")
+	}
+	return b.String()
+}
+
+func (p *RoutePlan) title() string {
+	if p.Method == "" {
+		return p.Path
+	}
+	return p.Method + " " + p.Path
+}
+
+func (p *RoutePlan) nodeIDs() []string {
+	ids := make([]string, 0, len(p.Operators))
+	for nodeID := range p.Operators {
+		ids = append(ids, nodeID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// nodeLabel 节点ID与算子名称不同时（如工厂创建的实例）同时显示两者
+func (p *RoutePlan) nodeLabel(nodeID string) string {
+	op := p.Operators[nodeID]
+	switch op {
+	case "":
+		return nodeID + " (unresolved)"
+	case nodeID:
+		return nodeID
+	default:
+		return nodeID + " (" + op + ")"
+	}
+}
+
+// mermaidText Mermaid 带引号的文本中不能直接出现双引号
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, "\"", "#quot;")
+}
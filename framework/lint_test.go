@@ -0,0 +1,205 @@
+package framework
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLintConfigDiagnostics(t *testing.T) {
+	registerTestOperator("lint_test_op", dataOperator("ok"))
+
+	tests := []struct {
+		name   string
+		config string
+		want   []string // 按顺序出现的诊断
+	}{
+		{
+			name:   "valid config",
+			config: `[{"server_name": "/a", "nodes": [{"name": "x", "operator": "lint_test_op", "downstream": "y"}, {"name": "y", "operator": "lint_test_op"}]}]`,
+		},
+		{
+			name:   "unknown operator",
+			config: `[{"server_name": "/a", "nodes": [{"name": "x", "operator": "lint_test_missing"}]}]`,
+			want:   []string{"error: /a node x: operator lint_test_missing not found in registry"},
+		},
+		{
+			name:   "unknown factory",
+			config: `[{"server_name": "/a", "nodes": [{"name": "x", "factory": "lint_test_missing"}]}]`,
+			want:   []string{"error: /a node x: operator factory lint_test_missing not found for node x"},
+		},
+		{
+			name: "cycle",
+			config: `[{"server_name": "/a", "nodes": [
+				{"name": "x", "operator": "lint_test_op", "downstream": "y"},
+				{"name": "y", "operator": "lint_test_op", "downstream": "z"},
+				{"name": "z", "operator": "lint_test_op", "downstream": "y"}
+			]}]`,
+			want: []string{"error: /a: cycle detected, nodes unreachable: y, z"},
+		},
+		{
+			name: "isolated node",
+			config: `[{"server_name": "/a", "nodes": [
+				{"name": "x", "operator": "lint_test_op", "downstream": "y"},
+				{"name": "y", "operator": "lint_test_op"},
+				{"name": "stray", "operator": "lint_test_op"}
+			]}]`,
+			want: []string{"warning: /a node stray: node has no edges and runs as an independent root"},
+		},
+		{
+			name: "condition errors",
+			config: `[{"server_name": "/a", "nodes": [
+				{"name": "x", "operator": "lint_test_op", "downstream": [{"node": "y", "when": "data.x.ok =="}, {"node": "z", "when": "data.w.ok"}]},
+				{"name": "y", "operator": "lint_test_op"},
+				{"name": "z", "operator": "lint_test_op"}
+			]}]`,
+			want: []string{"error: /a node y: edge x -> y:", "error: /a node z: edge x -> z: condition references unknown node w"},
+		},
+		{
+			name: "condition on a node that never runs first",
+			config: `[{"server_name": "/a", "nodes": [
+				{"name": "x", "operator": "lint_test_op", "downstream": [{"node": "y", "when": "data.z.ok"}]},
+				{"name": "y", "operator": "lint_test_op"},
+				{"name": "z", "operator": "lint_test_op", "downstream": "y"}
+			]}]`,
+			want: []string{"warning: /a node y: edge x -> y: condition references z, which never runs before this edge"},
+		},
+		{
+			name: "duplicate server_name",
+			config: `[
+				{"server_name": "/a", "nodes": [{"name": "lint_test_op"}]},
+				{"server_name": "/a", "nodes": [{"name": "lint_test_op"}]}
+			]`,
+			want: []string{"error: /a: duplicate server_name"},
+		},
+		{
+			name: "duplicate route path",
+			config: `[
+				{"server_name": "/a", "method": "GET", "path": "/items", "nodes": [{"name": "lint_test_op"}]},
+				{"server_name": "/b", "method": "GET", "path": "/items", "nodes": [{"name": "lint_test_op"}]}
+			]`,
+			want: []string{"error: /b: duplicate route GET /items (/a and /b)"},
+		},
+		{
+			name: "unused pipeline",
+			config: `{
+				"pipelines": {"used": {"nodes": [{"name": "lint_test_op"}]}, "unused": {"nodes": [{"name": "lint_test_op"}]}},
+				"routes": [{"server_name": "/a", "nodes": [{"name": "p", "include": "used"}]}]
+			}`,
+			want: []string{"warning: pipeline unused is not included by any route"},
+		},
+		{
+			name:   "invalid mode",
+			config: `[{"server_name": "/a", "mode": "eventually", "nodes": [{"name": "lint_test_op"}]}]`,
+			want:   []string{"error: /a:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewScheduler().LintConfig(writeConfig(t, tt.config))
+			if err != nil {
+				t.Fatalf("LintConfig: %v", err)
+			}
+
+			got := make([]string, 0, len(report.Diagnostics))
+			for _, d := range report.Diagnostics {
+				got = append(got, d.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("diagnostics = %q, want %q", got, tt.want)
+			}
+			wantErrors := false
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("diagnostic %d = %q, want prefix %q", i, got[i], want)
+				}
+				wantErrors = wantErrors || strings.HasPrefix(want, SeverityError)
+			}
+			if report.HasErrors() != wantErrors {
+				t.Errorf("HasErrors = %v, want %v", report.HasErrors(), wantErrors)
+			}
+		})
+	}
+}
+
+func TestLintConfigPlan(t *testing.T) {
+	registerTestOperator("lint_plan_root", dataOperator("root"))
+	registerTestOperator("lint_plan_leaf", dataOperator("leaf"))
+
+	report, err := NewScheduler().LintConfig(writeConfig(t, `[{
+		"server_name": "/plan", "method": "POST", "path": "/items/{id}", "mode": "parallel",
+		"nodes": [
+			{"name": "root", "operator": "lint_plan_root", "downstream": [{"node": "left"}, {"node": "right", "when": "data.root == \"root\""}]},
+			{"name": "left", "operator": "lint_plan_leaf", "downstream": "join"},
+			{"name": "right", "operator": "lint_plan_leaf", "downstream": "join"},
+			{"name": "join", "operator": "lint_plan_leaf"}
+		]
+	}]`))
+	if err != nil {
+		t.Fatalf("LintConfig: %v", err)
+	}
+	if len(report.Diagnostics) != 0 || len(report.Routes) != 1 {
+		t.Fatalf("diagnostics = %v, routes = %d", report.Diagnostics, len(report.Routes))
+	}
+
+	plan := report.Routes[0]
+	if plan.Method != "POST" || plan.Path != "/items/{id}" || plan.Mode != ModeParallel {
+		t.Errorf("plan = %s %s %s", plan.Method, plan.Path, plan.Mode)
+	}
+	wantOperators := map[string]string{"root": "lint_plan_root", "left": "lint_plan_leaf", "right": "lint_plan_leaf", "join": "lint_plan_leaf"}
+	if !reflect.DeepEqual(plan.Operators, wantOperators) {
+		t.Errorf("operators = %v, want %v", plan.Operators, wantOperators)
+	}
+
+	levels := make([]string, 0, len(plan.Levels))
+	for _, level := range plan.Levels {
+		sorted := append([]string(nil), level...)
+		sort.Strings(sorted) // 层级内节点顺序不固定
+		levels = append(levels, strings.Join(sorted, ","))
+	}
+	if got := strings.Join(levels, " | "); got != "root | left,right | join" {
+		t.Errorf("levels = %s", got)
+	}
+
+	wantEdges := []PlanEdge{
+		{From: "left", To: "join"},
+		{From: "right", To: "join"},
+		{From: "root", To: "left"},
+		{From: "root", To: "right", When: `data.root == "root"`},
+	}
+	if !reflect.DeepEqual(plan.Edges, wantEdges) {
+		t.Errorf("edges = %v, want %v", plan.Edges, wantEdges)
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		dot := report.DOT()
+		for _, want := range []string{
+			"digraph scheduler {\n",
+			`label="POST /items/{id}";`,
+			`"/plan/join" [label="join (lint_plan_leaf)"];`,
+			`"/plan/root" -> "/plan/left";`,
+			`"/plan/root" -> "/plan/right" [label="data.root == \"root\"", style=dashed];`,
+		} {
+			if !strings.Contains(dot, want) {
+				t.Errorf("DOT output lacks %q:\n%s", want, dot)
+			}
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		mermaid := report.Mermaid()
+		// 节点按ID排序编号：join=n0, left=n1, right=n2, root=n3
+		for _, want := range []string{
+			"flowchart LR\n",
+			`subgraph r0["POST /items/{id}"]`,
+			`r0n0["join (lint_plan_leaf)"]`,
+			"r0n3 --> r0n1",
+			`r0n3 -.->|"data.root == #quot;root#quot;"| r0n2`,
+		} {
+			if !strings.Contains(mermaid, want) {
+				t.Errorf("Mermaid output lacks %q:\n%s", want, mermaid)
+			}
+		}
+	})
+}
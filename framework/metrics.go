@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/metrics"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// 调度器内置指标，注册在默认注册表中
+var (
+	routeRequests = metrics.NewCounter("scheduler_route_requests_total",
+		"Route executions by route and response status code.", "route", "status")
+	routeDuration = metrics.NewHistogram("scheduler_route_duration_seconds",
+		"Route execution latency in seconds.", nil, "route")
+	routesInFlight = metrics.NewGauge("scheduler_route_in_flight",
+		"Route executions currently in progress.", "route")
+	nodeDuration = metrics.NewHistogram("scheduler_operator_duration_seconds",
+		"Operator latency in seconds, including retries.", nil, "route", "node")
+	nodeErrors = metrics.NewCounter("scheduler_operator_errors_total",
+		"Failed operator executions by status code.", "route", "node", "status")
+	compensations = metrics.NewCounter("scheduler_compensations_total",
+		"Compensations run after a route failed, by outcome.", "route", "node", "outcome")
+	breakerState = metrics.NewGauge("scheduler_circuit_breaker_state",
+		"Circuit breaker state: 0 closed, 1 open, 2 half-open.", "breaker")
+	breakerTransitions = metrics.NewCounter("scheduler_circuit_breaker_transitions_total",
+		"Circuit breaker state transitions, by new state.", "breaker", "state")
+	breakerRejections = metrics.NewCounter("scheduler_circuit_breaker_rejections_total",
+		"Executions rejected or served by fallback while a breaker was open.", "breaker")
+	workersBusy = metrics.NewGauge("scheduler_workers_busy",
+		"Worker pool slots held by operators running in parallel mode.")
+	nodePanicCount = metrics.NewCounter("scheduler_operator_panics_total",
+		"Recovered operator panics.", "route", "node")
+)
+
+func init() {
+	OnPanic(func(serverName, nodeID string) { nodePanicCount.Inc(serverName, nodeID) })
+}
+
+// statusOf 错误对应的HTTP状态码：ApiError 使用其状态码，其余错误视为500
+func statusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var apiErr *types.ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// observeRoute 记录一次路由执行
+func observeRoute(serverName string, output *ExecutionOutput, err error, elapsed time.Duration) {
+	status := statusOf(err)
+	if err == nil && output.Response != nil && output.Response.StatusCode != 0 {
+		status = output.Response.StatusCode
+	}
+	routeRequests.Inc(serverName, strconv.Itoa(status))
+	routeDuration.Observe(elapsed.Seconds(), serverName)
+}
+
+// observeNode 记录一次节点执行
+func observeNode(serverName string, result ExecutionResult, elapsed time.Duration) {
+	nodeDuration.Observe(elapsed.Seconds(), serverName, result.OperatorName)
+	if result.Error != nil {
+		nodeErrors.Inc(serverName, result.OperatorName, strconv.Itoa(statusOf(result.Error)))
+	}
+}
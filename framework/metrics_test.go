@@ -0,0 +1,84 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zhanghuachuan/water-reminder/metrics"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// metricValue 返回默认注册表中名称与标签为 series 的指标值，不存在时为 0
+func metricValue(t *testing.T, series string) float64 {
+	t.Helper()
+	var b strings.Builder
+	metrics.Default.WriteText(&b)
+	for _, line := range strings.Split(b.String(), "\n") {
+		if value, ok := strings.CutPrefix(line, series+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func TestRouteMetricsKeepOperatorStatus(t *testing.T) {
+	registerTestOperator("metrics_test_ok", dataOperator("ok"))
+	registerTestOperator("metrics_test_not_found", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("记录不存在", "Not found", http.StatusNotFound)}
+	})
+	registerTestOperator("metrics_test_bad_request", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("参数错误", "Bad request", http.StatusBadRequest)}
+	})
+
+	tests := []struct {
+		name       string
+		serverName string
+		nodes      string
+		mode       string
+		wantStatus int
+	}{
+		{
+			name:       "sequential",
+			serverName: "/metrics_seq",
+			nodes:      `[{"name": "metrics_test_not_found"}]`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "one failure in a parallel level",
+			serverName: "/metrics_par",
+			mode:       ModeParallel,
+			nodes:      `[{"name": "metrics_test_ok"}, {"name": "metrics_test_not_found"}]`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "several failures in a parallel level",
+			serverName: "/metrics_par_multi",
+			mode:       ModeParallel,
+			nodes:      `[{"name": "a", "operator": "metrics_test_bad_request"}, {"name": "b", "operator": "metrics_test_bad_request"}]`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, `[{"server_name": "`+tt.serverName+`", "mode": "`+tt.mode+`", "nodes": `+tt.nodes+`}]`)
+
+			series := `scheduler_route_requests_total{route="` + tt.serverName + `",status="` + strconv.Itoa(tt.wantStatus) + `"}`
+			before := metricValue(t, series)
+
+			_, err := s.Run(context.Background(), tt.serverName, ExecuteOptions{})
+			if got := statusOf(err); got != tt.wantStatus {
+				t.Errorf("statusOf(%v) = %d, want %d", err, got, tt.wantStatus)
+			}
+			if got := metricValue(t, series) - before; got != 1 {
+				t.Errorf("%s increased by %v, want 1", series, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package framework
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion 生成的文档使用 OpenAPI 3.1，其 schema 即 JSON Schema 2020-12
+const OpenAPIVersion = "3.1.0"
+
+// OpenAPIInfo 文档的基本信息
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIDocument OpenAPI 文档
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+// OpenAPIComponents 可复用的组件
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema                `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPISecurityScheme 认证方式
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// OpenAPIOperation 单个路由的接口描述
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+}
+
+// OpenAPIParameter 路径或查询参数
+type OpenAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// OpenAPIRequestBody 请求体
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse 响应
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType 内容类型对应的 schema
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	bearerAuthScheme  = "bearerAuth"
+	errorResponseName = "ErrorResponse"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// OpenAPI 根据当前生效的路由与算子声明的 SchemaProvider 生成 OpenAPI 文档。
+//
+// 请求体取执行顺序中第一个声明了 Request 的算子，查询参数合并全部算子的声明；
+// 响应数据按路由的 response 配置取对应节点的 Response，未配置时取唯一的最后一层节点。
+// 成功响应与错误响应使用与 HTTP 接口一致的 ApiResponse 包装。
+// 未限定方法的路由按 POST 描述。
+func (s *Scheduler) OpenAPI(info OpenAPIInfo) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+		Components: OpenAPIComponents{
+			Schemas: map[string]*Schema{
+				errorResponseName: {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"success": {Type: "boolean"},
+						"message": {Type: "string"},
+						"error":   {Type: "string"},
+						"details": {
+							Type: "array",
+							Items: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"in":      {Type: "string", Enum: []interface{}{"body", "query"}},
+									"field":   {Type: "string"},
+									"message": {Type: "string"},
+								},
+							},
+						},
+					},
+					Required: []string{"success"},
+				},
+			},
+		},
+	}
+
+	table := s.plans.Load()
+	names := make([]string, 0, len(table.plans))
+	for name := range table.plans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plan := table.plans[name]
+		doc.addOperation(plan, plan.method, plan.path, operationID(plan.serverName), false)
+		// 附加入口作为已弃用的接口列出
+		for i, alias := range plan.aliases {
+			doc.addOperation(plan, alias.Method, alias.Path, operationID(plan.serverName)+"_alias"+strconv.Itoa(i+1), true)
+		}
+	}
+	return doc
+}
+
+// addOperation 将路由在 method 与 path 上的入口加入文档，未限定方法的路由按 POST 描述
+func (doc *OpenAPIDocument) addOperation(plan *executionPlan, method, path, id string, deprecated bool) {
+	method = strings.ToUpper(method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	openAPIPath := pathParamPattern.ReplaceAllStringFunc(path, func(param string) string {
+		// 路由器的 {name...} 通配参数在 OpenAPI 中写作 {name}
+		return strings.TrimSuffix(param[:len(param)-1], "...") + "}"
+	})
+	if doc.Paths[openAPIPath] == nil {
+		doc.Paths[openAPIPath] = make(map[string]*OpenAPIOperation)
+	}
+	operation := plan.openAPIOperation(method, path)
+	operation.OperationID = id
+	operation.Deprecated = deprecated
+	if operation.Security != nil {
+		doc.Components.SecuritySchemes = map[string]*OpenAPISecurityScheme{
+			bearerAuthScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+	}
+	doc.Paths[openAPIPath][strings.ToLower(method)] = operation
+}
+
+// openAPIOperation 生成路由在 path 上的接口描述
+func (p *executionPlan) openAPIOperation(method, path string) *OpenAPIOperation {
+	operation := &OpenAPIOperation{
+		OperationID: operationID(p.serverName),
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+
+	schemas := make(map[string]*OperatorSchema, len(p.operators))
+	errors := map[int]bool{http.StatusInternalServerError: true}
+	queryParams := make(map[string]*OpenAPIParameter)
+	for _, level := range p.levels {
+		for _, nodeID := range level {
+			schema := SchemaFor(p.operators[nodeID], method)
+			if schema == nil {
+				continue
+			}
+			schemas[nodeID] = schema
+
+			// 认证等前置算子先执行，接口说明取最后执行的业务算子
+			if schema.Summary != "" {
+				operation.Summary = schema.Summary
+			}
+			if schema.Request != nil && operation.RequestBody == nil && methodHasBody(method) {
+				operation.RequestBody = &OpenAPIRequestBody{
+					Required: true,
+					Content:  jsonContent(SchemaOf(schema.Request)),
+				}
+			}
+			if query := SchemaOf(schema.Query); query != nil {
+				for name, property := range query.Properties {
+					queryParams[name] = &OpenAPIParameter{Name: name, In: "query", Required: contains(query.Required, name), Schema: property}
+				}
+			}
+			for _, status := range schema.Errors {
+				errors[status] = true
+			}
+			if schema.Auth {
+				operation.Security = []map[string][]string{{bearerAuthScheme: {}}}
+			}
+		}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		name := strings.TrimSuffix(match[1], "...")
+		operation.Parameters = append(operation.Parameters, &OpenAPIParameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	names := make([]string, 0, len(queryParams))
+	for name := range queryParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		operation.Parameters = append(operation.Parameters, queryParams[name])
+	}
+
+	// 调度器自身产生的错误：请求体过大、超时
+	if operation.RequestBody != nil {
+		errors[http.StatusRequestEntityTooLarge] = true
+	}
+	if p.timeout > 0 || len(p.nodeTimeouts) > 0 {
+		errors[http.StatusGatewayTimeout] = true
+	}
+
+	operation.Responses[strconv.Itoa(http.StatusOK)] = &OpenAPIResponse{
+		Description: "操作成功",
+		Content: jsonContent(&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"success": {Type: "boolean"},
+				"message": {Type: "string"},
+				"data":    p.responseSchema(schemas),
+			},
+			Required: []string{"success"},
+		}),
+	}
+	for status := range errors {
+		operation.Responses[strconv.Itoa(status)] = &OpenAPIResponse{
+			Description: http.StatusText(status),
+			Content:     jsonContent(&Schema{Ref: "#/components/schemas/" + errorResponseName}),
+		}
+	}
+	return operation
+}
+
+// responseSchema 路由响应数据的 schema，与 buildResponse 的取值规则一致
+func (p *executionPlan) responseSchema(schemas map[string]*OperatorSchema) *Schema {
+	dataOf := func(nodeID string) *Schema {
+		if schema := schemas[nodeID]; schema != nil && schema.Response != nil {
+			return SchemaOf(schema.Response)
+		}
+		return &Schema{}
+	}
+
+	switch {
+	case p.response == nil:
+		if len(p.levels) == 0 || len(p.levels[len(p.levels)-1]) != 1 {
+			return &Schema{}
+		}
+		return dataOf(p.levels[len(p.levels)-1][0])
+	case p.response.From != "":
+		return dataOf(p.response.From)
+	default:
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(p.response.Compose))}
+		for field, nodeID := range p.response.Compose {
+			schema.Properties[field] = dataOf(nodeID)
+		}
+		return schema
+	}
+}
+
+// operationID 由 server_name 生成，如 /create_drinking_record -> create_drinking_record
+func operationID(serverName string) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, serverName)
+	return strings.Trim(id, "_")
+}
+
+func methodHasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+func jsonContent(schema *Schema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
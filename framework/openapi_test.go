@@ -0,0 +1,217 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// schemaOperator 按请求方法声明 schema 的测试算子
+type schemaOperator struct {
+	testOperator
+	schemas map[string]*OperatorSchema // 键为空时对全部方法生效
+}
+
+func (o *schemaOperator) Schema(method string) *OperatorSchema {
+	if schema, ok := o.schemas[method]; ok {
+		return schema
+	}
+	return o.schemas[""]
+}
+
+func registerSchemaOperator(name string, schemas map[string]*OperatorSchema) {
+	RegisterOperator(name, &schemaOperator{testOperator: testOperator{name: name, execute: dataOperator(nil)}, schemas: schemas})
+}
+
+type openAPITestRecord struct {
+	ID     int `json:"id"`
+	Amount int `json:"amount"`
+}
+
+type openAPITestCreate struct {
+	Amount int    `json:"amount" schema:"required,min=1"`
+	Note   string `json:"note"`
+}
+
+type openAPITestQuery struct {
+	Page int `json:"page" schema:"required"`
+	Size int `json:"size"`
+}
+
+type openAPITestStats struct {
+	Total int `json:"total"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	registerSchemaOperator("openapi_test_auth", map[string]*OperatorSchema{"": {Auth: true, Errors: []int{http.StatusUnauthorized}}})
+	registerSchemaOperator("openapi_test_records", map[string]*OperatorSchema{
+		http.MethodPost: {Summary: "创建记录", Request: openAPITestCreate{}, Response: openAPITestRecord{}, Errors: []int{http.StatusBadRequest}},
+		http.MethodGet:  {Summary: "查询记录", Query: openAPITestQuery{}, Response: []openAPITestRecord{}},
+	})
+	registerSchemaOperator("openapi_test_stats", map[string]*OperatorSchema{"": {Response: openAPITestStats{}}})
+	registerTestOperator("openapi_test_plain", dataOperator("plain"))
+
+	s := newTestScheduler(t, `[
+		{"server_name": "/create_record", "path": "/records", "nodes": [
+			{"name": "openapi_test_auth", "downstream": "openapi_test_records"},
+			{"name": "openapi_test_records"}
+		]},
+		{"server_name": "/list_records", "method": "GET", "path": "/users/{user}/records/{rest...}", "nodes": [
+			{"name": "openapi_test_records", "timeout": "500ms"}
+		]},
+		{"server_name": "/dashboard", "method": "GET", "timeout": "1s", "response": {"compose": {"records": "records", "stats": "stats"}}, "nodes": [
+			{"name": "records", "operator": "openapi_test_records"},
+			{"name": "stats", "operator": "openapi_test_stats"}
+		]},
+		{"server_name": "/plain", "method": "PUT", "nodes": [{"name": "openapi_test_plain"}]}
+	]`)
+	doc := s.OpenAPI(OpenAPIInfo{Title: "test", Version: "1.0"})
+
+	if doc.OpenAPI != OpenAPIVersion || doc.Info.Title != "test" {
+		t.Errorf("header = %s %+v", doc.OpenAPI, doc.Info)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	// {rest...} 通配参数写作 {rest}，未配置 path 的路由使用 server_name
+	if want := []string{"/dashboard", "/plain", "/records", "/users/{user}/records/{rest}"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	if scheme := doc.Components.SecuritySchemes[bearerAuthScheme]; scheme == nil || scheme.Scheme != "bearer" {
+		t.Errorf("security scheme = %+v", scheme)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		method       string
+		wantID       string
+		wantSummary  string
+		wantParams   []string // in:name，必填的带 *
+		wantBody     []string // 请求体必填字段，nil 表示没有请求体
+		wantStatuses []string
+		wantSecurity bool
+		wantData     *Schema // 成功响应 data 的 schema
+	}{
+		{
+			name:         "unrestricted route is described as POST with a body",
+			path:         "/records",
+			method:       "post",
+			wantID:       "create_record",
+			wantSummary:  "创建记录",
+			wantBody:     []string{"amount"},
+			wantStatuses: []string{"200", "400", "401", "413", "500"},
+			wantSecurity: true,
+			wantData:     SchemaOf(openAPITestRecord{}),
+		},
+		{
+			name:         "path and query parameters with a node timeout",
+			path:         "/users/{user}/records/{rest}",
+			method:       "get",
+			wantID:       "list_records",
+			wantSummary:  "查询记录",
+			wantParams:   []string{"path:user*", "path:rest*", "query:page*", "query:size"},
+			wantStatuses: []string{"200", "500", "504"},
+			wantData:     SchemaOf([]openAPITestRecord{}),
+		},
+		{
+			name:         "composed response with a route timeout",
+			path:         "/dashboard",
+			method:       "get",
+			wantID:       "dashboard",
+			wantSummary:  "查询记录",
+			wantParams:   []string{"query:page*", "query:size"},
+			wantStatuses: []string{"200", "500", "504"},
+			wantData: &Schema{Type: "object", Properties: map[string]*Schema{
+				"records": SchemaOf([]openAPITestRecord{}),
+				"stats":   SchemaOf(openAPITestStats{}),
+			}},
+		},
+		{
+			name:         "operator without schema",
+			path:         "/plain",
+			method:       "put",
+			wantID:       "plain",
+			wantStatuses: []string{"200", "500"},
+			wantData:     &Schema{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation := doc.Paths[tt.path][tt.method]
+			if operation == nil {
+				t.Fatalf("no %s %s operation, got %v", tt.method, tt.path, doc.Paths[tt.path])
+			}
+			if operation.OperationID != tt.wantID || operation.Summary != tt.wantSummary || operation.Deprecated {
+				t.Errorf("operation = %s %q deprecated=%v", operation.OperationID, operation.Summary, operation.Deprecated)
+			}
+
+			var params []string
+			for _, param := range operation.Parameters {
+				name := param.In + ":" + param.Name
+				if param.Required {
+					name += "*"
+				}
+				params = append(params, name)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("parameters = %v, want %v", params, tt.wantParams)
+			}
+
+			switch {
+			case tt.wantBody == nil && operation.RequestBody != nil:
+				t.Error("unexpected request body")
+			case tt.wantBody != nil && operation.RequestBody == nil:
+				t.Error("missing request body")
+			case tt.wantBody != nil:
+				body := operation.RequestBody.Content["application/json"].Schema
+				if !operation.RequestBody.Required || !reflect.DeepEqual(body.Required, tt.wantBody) {
+					t.Errorf("request body required = %v, fields %v", operation.RequestBody.Required, body.Required)
+				}
+			}
+
+			statuses := make([]string, 0, len(operation.Responses))
+			for status, response := range operation.Responses {
+				statuses = append(statuses, status)
+				if status != "200" && response.Content["application/json"].Schema.Ref != "#/components/schemas/"+errorResponseName {
+					t.Errorf("response %s does not use %s", status, errorResponseName)
+				}
+			}
+			sort.Strings(statuses)
+			if !reflect.DeepEqual(statuses, tt.wantStatuses) {
+				t.Errorf("responses = %v, want %v", statuses, tt.wantStatuses)
+			}
+
+			if (operation.Security != nil) != tt.wantSecurity {
+				t.Errorf("security = %v, want %v", operation.Security, tt.wantSecurity)
+			}
+
+			data := operation.Responses["200"].Content["application/json"].Schema.Properties["data"]
+			if !reflect.DeepEqual(data, tt.wantData) {
+				got, _ := json.Marshal(data)
+				want, _ := json.Marshal(tt.wantData)
+				t.Errorf("data schema = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := map[string]string{
+		"/create_drinking_record": "create_drinking_record",
+		"/records/by-day":         "records_by_day",
+		"/v1/Users":               "v1_Users",
+	}
+	for serverName, want := range tests {
+		if got := operationID(serverName); got != want {
+			t.Errorf("operationID(%q) = %q, want %q", serverName, got, want)
+		}
+	}
+}
@@ -1,20 +1,18 @@
 package framework
 
-import (
-	"context"
-	"net/http"
-)
+import "context"
 
 // OperatorResult 算子执行结果
 type OperatorResult struct {
-	Data  interface{} // 成功时的业务数据
-	Error error       // 错误信息
+	Data     interface{} // 成功时的业务数据
+	Error    error       // 错误信息
+	Response *Response   // 可选：控制HTTP状态码、响应头、Cookie与响应体格式
 }
 
-// Operator 算子接口
+// Operator 算子接口，请求与传输方式无关；需要 HTTP 特有信息时使用 req.HTTP(ctx)
 type Operator interface {
 	Name() string
-	Execute(ctx context.Context, r *http.Request) (context.Context, *OperatorResult)
+	Execute(ctx context.Context, req *Request) (context.Context, *OperatorResult)
 }
 
 // OperatorFactory 算子工厂接口
@@ -22,3 +20,9 @@ type OperatorFactory interface {
 	Name() string
 	Create(config map[string]interface{}) (Operator, error)
 }
+
+// NodeOperatorFactory 可选接口：需要按节点保存跨配置重载状态的工厂实现，调度器创建节点时传入路由与节点ID
+type NodeOperatorFactory interface {
+	OperatorFactory
+	CreateNode(serverName, nodeID string, config map[string]interface{}) (Operator, error)
+}
@@ -1,6 +1,10 @@
 package framework
 
-import "sync"
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
 
 // BaseOperatorFactory 基础算子工厂
 type BaseOperatorFactory struct {
@@ -33,6 +37,29 @@ func GetOperatorFactory() map[string]OperatorFactory {
 	return factories
 }
 
+// lookupOperatorFactory 按名称查找算子工厂
+func lookupOperatorFactory(name string) (OperatorFactory, bool) {
+	factoryMutex.RLock()
+	defer factoryMutex.RUnlock()
+
+	factory, exists := operatorFactories[name]
+	return factory, exists
+}
+
+// DecodeConfig 将节点的 config 映射解码到结构体，便于工厂读取带类型的配置
+func DecodeConfig(config map[string]interface{}, out interface{}) error {
+	if len(config) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
+
 func NewOperatorFactory(
 	name string,
 	creator func(config map[string]interface{}) (Operator, error),
@@ -50,3 +77,32 @@ func (f *BaseOperatorFactory) Name() string {
 func (f *BaseOperatorFactory) Create(config map[string]interface{}) (Operator, error) {
 	return f.creator(config)
 }
+
+// nodeOperatorFactory 创建时需要路由与节点ID的算子工厂
+type nodeOperatorFactory struct {
+	name    string
+	creator func(serverName, nodeID string, config map[string]interface{}) (Operator, error)
+}
+
+// NewNodeOperatorFactory 创建按路由与节点ID构造算子的工厂，未经调度器直接调用 Create 时路由为空、以工厂名作为节点ID
+func NewNodeOperatorFactory(
+	name string,
+	creator func(serverName, nodeID string, config map[string]interface{}) (Operator, error),
+) NodeOperatorFactory {
+	return &nodeOperatorFactory{
+		name:    name,
+		creator: creator,
+	}
+}
+
+func (f *nodeOperatorFactory) Name() string {
+	return f.name
+}
+
+func (f *nodeOperatorFactory) Create(config map[string]interface{}) (Operator, error) {
+	return f.creator("", f.name, config)
+}
+
+func (f *nodeOperatorFactory) CreateNode(serverName, nodeID string, config map[string]interface{}) (Operator, error) {
+	return f.creator(serverName, nodeID, config)
+}
@@ -0,0 +1,160 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// QuarantinePolicy 路由级别的隔离策略：节点在 window 内 panic 达到 max_panics 次后，
+// 在 duration 内不再执行，直接返回503
+type QuarantinePolicy struct {
+	MaxPanics int      `json:"max_panics"`
+	Window    Duration `json:"window"`
+	Duration  Duration `json:"duration"`
+}
+
+func (p *QuarantinePolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxPanics < 1 {
+		return fmt.Errorf("quarantine max_panics must be at least 1")
+	}
+	if p.Window.Duration <= 0 || p.Duration.Duration <= 0 {
+		return fmt.Errorf("quarantine window and duration must be positive")
+	}
+	return nil
+}
+
+// panicTracker 记录各路由节点的 panic 次数与隔离状态，挂在调度器上，配置重载后依然保留
+type panicTracker struct {
+	mu    sync.Mutex
+	nodes map[string]*nodePanics // "server_name node" -> 统计
+}
+
+type nodePanics struct {
+	serverName       string
+	node             string
+	total            uint64
+	recent           []time.Time
+	quarantinedUntil time.Time
+}
+
+// PanicStat 单个节点的 panic 统计
+type PanicStat struct {
+	ServerName       string    `json:"serverName"`
+	Node             string    `json:"node"`
+	Total            uint64    `json:"total"`
+	QuarantinedUntil time.Time `json:"quarantinedUntil"`
+}
+
+func newPanicTracker() *panicTracker {
+	return &panicTracker{nodes: make(map[string]*nodePanics)}
+}
+
+func panicTrackerKey(serverName, nodeID string) string {
+	return serverName + " " + nodeID
+}
+
+// record 记录一次 panic，并按隔离策略判断是否需要隔离该节点
+func (t *panicTracker) record(serverName, nodeID string, policy *QuarantinePolicy, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := panicTrackerKey(serverName, nodeID)
+	stat, exists := t.nodes[key]
+	if !exists {
+		stat = &nodePanics{serverName: serverName, node: nodeID}
+		t.nodes[key] = stat
+	}
+	stat.total++
+
+	if policy == nil {
+		return
+	}
+
+	// 只保留窗口内的 panic 记录
+	cutoff := now.Add(-policy.Window.Duration)
+	recent := stat.recent[:0]
+	for _, at := range stat.recent {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	stat.recent = append(recent, now)
+
+	if len(stat.recent) >= policy.MaxPanics {
+		stat.quarantinedUntil = now.Add(policy.Duration.Duration)
+		stat.recent = nil
+	}
+}
+
+// quarantined 判断节点当前是否处于隔离期
+func (t *panicTracker) quarantined(serverName, nodeID string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stat, exists := t.nodes[panicTrackerKey(serverName, nodeID)]
+	return exists && now.Before(stat.quarantinedUntil)
+}
+
+// snapshot 按路由与节点排序返回全部统计
+func (t *panicTracker) snapshot() []PanicStat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]PanicStat, 0, len(t.nodes))
+	for _, stat := range t.nodes {
+		stats = append(stats, PanicStat{
+			ServerName:       stat.serverName,
+			Node:             stat.node,
+			Total:            stat.total,
+			QuarantinedUntil: stat.quarantinedUntil,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ServerName != stats[j].ServerName {
+			return stats[i].ServerName < stats[j].ServerName
+		}
+		return stats[i].Node < stats[j].Node
+	})
+	return stats
+}
+
+// PanicHook 算子 panic 被恢复后调用，用于接入监控与告警
+type PanicHook func(serverName, nodeID string)
+
+var (
+	panicHooksMu sync.RWMutex
+	panicHooks   []PanicHook
+)
+
+// OnPanic 注册 panic hook，对全部调度器生效；hook 同步执行，应尽快返回
+func OnPanic(hook PanicHook) {
+	panicHooksMu.Lock()
+	defer panicHooksMu.Unlock()
+	panicHooks = append(panicHooks, hook)
+}
+
+// notifyPanic 依次调用已注册的 panic hook
+func notifyPanic(serverName, nodeID string) {
+	panicHooksMu.RLock()
+	hooks := panicHooks
+	panicHooksMu.RUnlock()
+	for _, hook := range hooks {
+		hook(serverName, nodeID)
+	}
+}
+
+func panicError(nodeID string) error {
+	return types.NewApiError("服务内部错误", "Operator "+nodeID+" panicked", http.StatusInternalServerError)
+}
+
+func quarantinedError(nodeID string) error {
+	return types.NewApiError("服务暂不可用", "Operator "+nodeID+" is quarantined after repeated panics", http.StatusServiceUnavailable)
+}
@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestPanicRecoveryAndQuarantine(t *testing.T) {
+	registerTestOperator("panic_test_boom", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		panic("boom")
+	})
+
+	var mu sync.Mutex
+	hooked := 0
+	OnPanic(func(serverName, nodeID string) {
+		if serverName == "/panic" && nodeID == "panic_test_boom" {
+			mu.Lock()
+			hooked++
+			mu.Unlock()
+		}
+	})
+
+	s := newTestScheduler(t, `[{
+	  "server_name": "/panic",
+	  "quarantine": {"max_panics": 2, "window": "1m", "duration": "1m"},
+	  "nodes": [{"name": "panic_test_boom"}]
+	}]`)
+
+	tests := []struct {
+		name       string
+		wantStatus int
+		wantHooked int
+	}{
+		{name: "first panic is recovered", wantStatus: http.StatusInternalServerError, wantHooked: 1},
+		{name: "second panic quarantines the node", wantStatus: http.StatusInternalServerError, wantHooked: 2},
+		{name: "quarantined node is not executed", wantStatus: http.StatusServiceUnavailable, wantHooked: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _ := s.Run(context.Background(), "/panic", ExecuteOptions{})
+			if output == nil || len(output.Results) != 1 {
+				t.Fatalf("unexpected output %+v", output)
+			}
+			if got := statusOf(output.Results[0].Error); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%v)", got, tt.wantStatus, output.Results[0].Error)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if hooked != tt.wantHooked {
+				t.Errorf("hook calls = %d, want %d", hooked, tt.wantHooked)
+			}
+		})
+	}
+
+	stats := s.PanicStats()
+	if len(stats) != 1 || stats[0].Total != 2 || stats[0].QuarantinedUntil.IsZero() {
+		t.Errorf("panic stats = %+v", stats)
+	}
+}
@@ -0,0 +1,302 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ConfigFile 调度配置文件
+//
+// 支持两种写法：
+//   - 路由数组: [{"server_name": ...}, ...]
+//   - 对象: {"pipelines": {...}, "routes": [...]}，pipelines 中定义可复用的子图
+type ConfigFile struct {
+	Pipelines map[string]PipelineConfig `json:"pipelines,omitempty"`
+	Routes    []RouteConfig             `json:"routes"`
+}
+
+// PipelineConfig 可复用的子图，写法与路由的 dependencies / nodes 相同
+type PipelineConfig struct {
+	Dependencies map[string]Edges `json:"dependencies,omitempty"`
+	Nodes        []NodeConfig     `json:"nodes,omitempty"`
+}
+
+// readConfigFile 读取并解析调度配置文件
+func readConfigFile(configPath string) (*ConfigFile, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file := &ConfigFile{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &file.Routes)
+	} else {
+		err = json.Unmarshal(data, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for name := range file.Pipelines {
+		if name == "" {
+			return nil, fmt.Errorf("pipeline name is required")
+		}
+		for _, route := range file.Routes {
+			if route.ServerName == name {
+				return nil, fmt.Errorf("pipeline %s has the same name as a route", name)
+			}
+		}
+	}
+	return file, nil
+}
+
+// expandRoute 在加载时展开路由中的 include 节点，返回只包含普通节点的路由配置与展开记录。
+//
+// include 节点展开为被引用的管道或路由的全部节点，节点ID加上 "<include 节点名>/" 前缀；
+// 指向 include 节点的边连接到子图的入口节点，从 include 节点出发的边从子图的出口节点出发。
+// 条件表达式与 response 中引用 include 节点时，指向子图唯一的出口节点。
+// 被引用的路由只使用其节点与边，超时、模式、响应等路由级配置不生效。
+func (f *ConfigFile) expandRoute(config RouteConfig) (RouteConfig, map[string]string, error) {
+	if !config.hasIncludes() {
+		return config, nil, nil
+	}
+
+	includes := make(map[string]string)
+	nodes, err := f.expandGraph(config, []string{config.ServerName}, "", includes)
+	if err != nil {
+		return config, includes, err
+	}
+
+	expanded := config
+	expanded.Dependencies = nil
+	expanded.Nodes = nodes.list
+	if config.Response != nil {
+		response := *config.Response
+		if response.From != "" {
+			if response.From, err = nodes.output(response.From); err != nil {
+				return config, includes, fmt.Errorf("response: %w", err)
+			}
+		}
+		if len(response.Compose) > 0 {
+			response.Compose = make(map[string]string, len(config.Response.Compose))
+			for field, nodeID := range config.Response.Compose {
+				if response.Compose[field], err = nodes.output(nodeID); err != nil {
+					return config, includes, fmt.Errorf("response: %w", err)
+				}
+			}
+		}
+		expanded.Response = &response
+	}
+	return expanded, includes, nil
+}
+
+// hasIncludes 路由中是否存在 include 节点
+func (c *RouteConfig) hasIncludes() bool {
+	for _, node := range c.Nodes {
+		if node.Include != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupInclude 按名称查找管道，找不到时查找同名路由
+func (f *ConfigFile) lookupInclude(name string) (RouteConfig, bool) {
+	if pipeline, exists := f.Pipelines[name]; exists {
+		return RouteConfig{ServerName: name, Dependencies: pipeline.Dependencies, Nodes: pipeline.Nodes}, true
+	}
+	for _, route := range f.Routes {
+		if route.ServerName == name {
+			return route, true
+		}
+	}
+	return RouteConfig{}, false
+}
+
+// expandedGraph 展开后的子图
+type expandedGraph struct {
+	list  []NodeConfig
+	roots map[string][]string // 节点名 -> 入口节点，普通节点为其自身
+	sinks map[string][]string // 节点名 -> 出口节点，普通节点为其自身
+}
+
+// output 条件与响应中引用的节点对应的展开后的节点ID，include 节点对应子图唯一的出口节点
+func (g *expandedGraph) output(name string) (string, error) {
+	sinks, exists := g.sinks[name]
+	if !exists {
+		return name, nil // 子图之外的节点保持原名，未知节点由后续校验报告
+	}
+	if len(sinks) != 1 {
+		return "", fmt.Errorf("include %s has %d exit nodes and cannot be referenced directly", name, len(sinks))
+	}
+	return sinks[0], nil
+}
+
+// expandGraph 递归展开 config 的图，prefix 为展开后节点ID的前缀，stack 为正在展开的名称用于检测循环引用
+func (f *ConfigFile) expandGraph(config RouteConfig, stack []string, prefix string, includes map[string]string) (*expandedGraph, error) {
+	graph, err := config.buildGraph()
+	if err != nil {
+		return nil, err
+	}
+	nodes := config.nodeConfigs(graph)
+
+	result := &expandedGraph{
+		roots: make(map[string][]string, len(nodes)),
+		sinks: make(map[string][]string, len(nodes)),
+	}
+	for _, name := range declarationOrder(config, graph) {
+		node := nodes[name]
+		id := prefix + name
+
+		if node.Include == "" {
+			if node.Operator == "" && node.Factory == "" {
+				node.Operator = name
+			}
+			node.Name = id
+			node.Upstream, node.Downstream = nil, nil
+			result.list = append(result.list, node)
+			result.roots[name] = []string{id}
+			result.sinks[name] = []string{id}
+			continue
+		}
+
+		if err := node.validateInclude(); err != nil {
+			return nil, fmt.Errorf("node %s: %w", name, err)
+		}
+		for _, seen := range stack {
+			if seen == node.Include {
+				return nil, fmt.Errorf("recursive include: %s -> %s", strings.Join(stack, " -> "), node.Include)
+			}
+		}
+		target, exists := f.lookupInclude(node.Include)
+		if !exists {
+			return nil, fmt.Errorf("node %s includes unknown pipeline or route %s", name, node.Include)
+		}
+		includes[id] = node.Include
+
+		sub, err := f.expandGraph(target, append(stack[:len(stack):len(stack)], node.Include), id+"/", includes)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", node.Include, err)
+		}
+		if len(sub.list) == 0 {
+			return nil, fmt.Errorf("node %s includes empty pipeline %s", name, node.Include)
+		}
+		result.list = append(result.list, sub.list...)
+		result.roots[name], result.sinks[name] = sub.entryExit()
+	}
+
+	// 按展开后的节点重新连接边，条件中引用的节点同时改名
+	edges := make(map[edgeKey]string)
+	var order []edgeKey
+	for _, from := range declarationOrder(config, graph) {
+		for _, to := range graph.downstream[from] {
+			when := graph.conditions[edgeKey{from: from, to: to}]
+			if when != "" {
+				if when, err = renameConditionNodes(when, result.output); err != nil {
+					return nil, fmt.Errorf("edge %s -> %s: %w", from, to, err)
+				}
+			}
+			for _, sink := range result.sinks[from] {
+				for _, root := range result.roots[to] {
+					key := edgeKey{from: sink, to: root}
+					if existing, ok := edges[key]; ok {
+						if existing != when {
+							return nil, fmt.Errorf("edge %s -> %s declared with conflicting conditions", sink, root)
+						}
+						continue
+					}
+					edges[key] = when
+					order = append(order, key)
+				}
+			}
+		}
+	}
+
+	index := make(map[string]int, len(result.list))
+	for i, node := range result.list {
+		index[node.Name] = i
+	}
+	for _, key := range order {
+		node := &result.list[index[key.from]]
+		node.Downstream = append(node.Downstream, Edge{Node: key.to, When: edges[key]})
+	}
+	return result, nil
+}
+
+// entryExit 子图的入口节点（没有上游）与出口节点（没有下游）
+func (g *expandedGraph) entryExit() (roots, sinks []string) {
+	hasUpstream := make(map[string]bool, len(g.list))
+	for _, node := range g.list {
+		for _, down := range node.Downstream {
+			hasUpstream[down.Node] = true
+		}
+	}
+	for _, node := range g.list {
+		if !hasUpstream[node.Name] {
+			roots = append(roots, node.Name)
+		}
+		if len(node.Downstream) == 0 {
+			sinks = append(sinks, node.Name)
+		}
+	}
+	return roots, sinks
+}
+
+// validateInclude include 节点只能声明名称与边，算子相关配置写在被引用的子图中
+func (n *NodeConfig) validateInclude() error {
+	if n.Operator != "" || n.Factory != "" || n.Config != nil || n.Retry != nil || n.Timeout.Duration != 0 || len(n.Wrap) > 0 {
+		return fmt.Errorf("include node must not set operator, factory, config, timeout, retry or wrap")
+	}
+	return nil
+}
+
+// declarationOrder 节点的展开顺序：nodes 中按声明顺序，dependencies 中按名称排序
+func declarationOrder(config RouteConfig, graph *routeGraph) []string {
+	order := make([]string, 0, len(graph.downstream))
+	seen := make(map[string]bool, len(graph.downstream))
+	for _, node := range config.Nodes {
+		order = append(order, node.Name)
+		seen[node.Name] = true
+	}
+	rest := make([]string, 0, len(graph.downstream))
+	for name := range graph.downstream {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+// renameConditionNodes 将条件表达式中 data.<节点> 引用的节点改名，其余内容保持语义不变
+func renameConditionNodes(source string, rename func(string) (string, error)) (string, error) {
+	tokens, err := tokenizeCondition(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid condition %q: %w", source, err)
+	}
+
+	parts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		switch token.kind {
+		case tokenString:
+			escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(token.text)
+			parts = append(parts, `"`+escaped+`"`)
+		case tokenIdent:
+			path := strings.Split(token.text, ".")
+			if len(path) >= 2 && path[0] == "data" {
+				if path[1], err = rename(path[1]); err != nil {
+					return "", err
+				}
+			}
+			parts = append(parts, strings.Join(path, "."))
+		default:
+			parts = append(parts, token.text)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
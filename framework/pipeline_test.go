@@ -0,0 +1,111 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "route includes itself",
+			config:  `[{"server_name": "/a", "nodes": [{"name": "self", "include": "/a"}]}]`,
+			wantErr: "recursive include: /a -> /a",
+		},
+		{
+			name: "pipelines include each other",
+			config: `{
+				"pipelines": {
+					"p1": {"nodes": [{"name": "x", "include": "p2"}]},
+					"p2": {"nodes": [{"name": "y", "include": "p1"}]}
+				},
+				"routes": [{"server_name": "/a", "nodes": [{"name": "n", "include": "p1"}]}]
+			}`,
+			wantErr: "recursive include: /a -> p1 -> p2 -> p1",
+		},
+		{
+			name: "routes include each other",
+			config: `[
+				{"server_name": "/a", "nodes": [{"name": "include_test_op", "downstream": "b"}, {"name": "b", "include": "/b"}]},
+				{"server_name": "/b", "nodes": [{"name": "a", "include": "/a"}]}
+			]`,
+			wantErr: "recursive include: /a -> /b -> /a",
+		},
+		{
+			name: "cycle deeper in the include chain",
+			config: `{
+				"pipelines": {
+					"outer": {"nodes": [{"name": "include_test_op", "downstream": "inner"}, {"name": "inner", "include": "inner"}]},
+					"inner": {"nodes": [{"name": "again", "include": "inner"}]}
+				},
+				"routes": [{"server_name": "/a", "nodes": [{"name": "n", "include": "outer"}]}]
+			}`,
+			wantErr: "recursive include: /a -> outer -> inner -> inner",
+		},
+		{
+			name:    "unknown include",
+			config:  `[{"server_name": "/a", "nodes": [{"name": "n", "include": "missing"}]}]`,
+			wantErr: "node n includes unknown pipeline or route missing",
+		},
+		{
+			name:    "include node with operator config",
+			config:  `{"pipelines": {"p": {"nodes": [{"name": "include_test_op"}]}}, "routes": [{"server_name": "/a", "nodes": [{"name": "n", "include": "p", "timeout": "1s"}]}]}`,
+			wantErr: "include node must not set operator",
+		},
+		{
+			name:    "pipeline named like a route",
+			config:  `{"pipelines": {"/a": {"nodes": [{"name": "include_test_op"}]}}, "routes": [{"server_name": "/a", "nodes": [{"name": "include_test_op"}]}]}`,
+			wantErr: "pipeline /a has the same name as a route",
+		},
+	}
+	registerTestOperator("include_test_op", dataOperator("ok"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewScheduler().LoadConfig(writeConfig(t, tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIncludeExpansion(t *testing.T) {
+	registerTestOperator("include_test_auth", dataOperator("auth"))
+	registerTestOperator("include_test_stats", dataOperator("stats"))
+
+	// 同一管道在一个路由中被引用两次不是递归，节点ID按 include 节点名加前缀区分
+	s := newTestScheduler(t, `{
+		"pipelines": {
+			"authenticated": {"nodes": [{"name": "include_test_auth"}]},
+			"twice": {"nodes": [
+				{"name": "first", "include": "authenticated", "downstream": "second"},
+				{"name": "second", "include": "authenticated"}
+			]}
+		},
+		"routes": [{"server_name": "/stats", "nodes": [
+			{"name": "prelude", "include": "twice", "downstream": "include_test_stats"},
+			{"name": "include_test_stats"}
+		]}]
+	}`)
+
+	output, err := s.Run(context.Background(), "/stats", ExecuteOptions{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var order []string
+	for _, result := range output.Results {
+		order = append(order, result.OperatorName)
+	}
+	if got := strings.Join(order, ","); got != "prelude/first/include_test_auth,prelude/second/include_test_auth,include_test_stats" {
+		t.Errorf("expanded nodes = %s", got)
+	}
+	if output.Body != "stats" {
+		t.Errorf("body = %v, want stats", output.Body)
+	}
+}
@@ -0,0 +1,113 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// 路由执行模式
+const (
+	ModeSequential = "sequential"
+	ModeParallel   = "parallel"
+)
+
+// DefaultMaxWorkers 全局同时执行的并行算子数量上限
+const DefaultMaxWorkers = 256
+
+// workerPool 限制所有请求并行执行的算子总数，避免高负载下无限创建 goroutine
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, size)}
+}
+
+// acquire 依次获取路由级与全局的执行槽位，上下文结束时放弃等待；routeSlots 为空表示路由不限制
+func (p *workerPool) acquire(ctx context.Context, routeSlots chan struct{}) error {
+	if routeSlots != nil {
+		select {
+		case routeSlots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		workersBusy.Inc()
+		return nil
+	case <-ctx.Done():
+		if routeSlots != nil {
+			<-routeSlots
+		}
+		return ctx.Err()
+	}
+}
+
+func (p *workerPool) release(routeSlots chan struct{}) {
+	<-p.slots
+	workersBusy.Dec()
+	if routeSlots != nil {
+		<-routeSlots
+	}
+}
+
+// slot 一个并行节点占用的执行槽位。
+// 超时后被放弃的算子 goroutine 仍在运行，槽位在节点与其全部算子 goroutine 都结束后才释放
+type slot struct {
+	pool       *workerPool
+	routeSlots chan struct{}
+	holders    atomic.Int32
+}
+
+type slotKey struct{}
+
+// newSlot 为已获取的槽位创建持有者计数，调用方是第一个持有者
+func (p *workerPool) newSlot(routeSlots chan struct{}) *slot {
+	s := &slot{pool: p, routeSlots: routeSlots}
+	s.holders.Store(1)
+	return s
+}
+
+// hold 增加一个持有者，s 为 nil 时不做任何事
+func (s *slot) hold() {
+	if s != nil {
+		s.holders.Add(1)
+	}
+}
+
+// done 移除一个持有者，最后一个持有者结束时释放槽位
+func (s *slot) done() {
+	if s != nil && s.holders.Add(-1) == 0 {
+		s.pool.release(s.routeSlots)
+	}
+}
+
+func withSlot(ctx context.Context, s *slot) context.Context {
+	return context.WithValue(ctx, slotKey{}, s)
+}
+
+// slotFromContext 节点占用的槽位，顺序执行时返回 nil
+func slotFromContext(ctx context.Context) *slot {
+	s, _ := ctx.Value(slotKey{}).(*slot)
+	return s
+}
+
+// validateMode 校验路由的执行模式与并发上限
+func (c *RouteConfig) validateMode() error {
+	switch c.Mode {
+	case "", ModeSequential:
+		if c.MaxConcurrency != 0 {
+			return fmt.Errorf("max_concurrency requires mode %s", ModeParallel)
+		}
+	case ModeParallel:
+		if c.MaxConcurrency < 0 {
+			return fmt.Errorf("max_concurrency must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown mode %s, expected %s or %s", c.Mode, ModeSequential, ModeParallel)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSlotHeldUntilAbandonedOperatorExits(t *testing.T) {
+	var mu sync.Mutex
+	var slowExited, fastStarted time.Time
+
+	// 不配合取消的算子，超时后 goroutine 仍在运行
+	registerTestOperator("pool_test_slow", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		time.Sleep(100 * time.Millisecond)
+		mu.Lock()
+		slowExited = time.Now()
+		mu.Unlock()
+		return ctx, &OperatorResult{}
+	})
+	registerTestOperator("pool_test_fast", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		mu.Lock()
+		fastStarted = time.Now()
+		mu.Unlock()
+		return ctx, &OperatorResult{}
+	})
+
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name: "route max_concurrency",
+			config: `[{"server_name": "/pool", "mode": "parallel", "max_concurrency": 1, "nodes": [
+				{"name": "a", "operator": "pool_test_slow", "timeout": "20ms"},
+				{"name": "b", "operator": "pool_test_fast"}
+			]}]`,
+		},
+		{
+			name: "retried attempts",
+			config: `[{"server_name": "/pool", "mode": "parallel", "max_concurrency": 1, "nodes": [
+				{"name": "a", "operator": "pool_test_slow", "timeout": "20ms", "retry": {"max_attempts": 2, "initial_backoff": "1ms", "retryable_errors": ["timeout"], "idempotent": true}},
+				{"name": "b", "operator": "pool_test_fast"}
+			]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slowExited, fastStarted = time.Time{}, time.Time{}
+			s := newTestScheduler(t, tt.config)
+			if _, err := s.Run(context.Background(), "/pool", ExecuteOptions{}); err == nil {
+				t.Fatal("expected the timed-out node to fail the route")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if fastStarted.IsZero() || slowExited.IsZero() {
+				t.Fatalf("slow exited at %v, fast started at %v", slowExited, fastStarted)
+			}
+			if fastStarted.Before(slowExited) {
+				t.Errorf("fast node started %v before the abandoned operator exited", slowExited.Sub(fastStarted))
+			}
+		})
+	}
+}
+
+// concurrencyProbe 记录同时运行的算子数量的峰值
+type concurrencyProbe struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (p *concurrencyProbe) operator(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+	p.mu.Lock()
+	p.running++
+	if p.running > p.peak {
+		p.peak = p.running
+	}
+	p.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	p.mu.Lock()
+	p.running--
+	p.mu.Unlock()
+	return ctx, &OperatorResult{}
+}
+
+func (p *concurrencyProbe) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running, p.peak = 0, 0
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	probe := &concurrencyProbe{}
+	registerTestOperator("pool_test_probe", probe.operator)
+
+	route := func(mode string, maxConcurrency int) string {
+		return `[{"server_name": "/limits", "mode": "` + mode + `", "max_concurrency": ` + strconv.Itoa(maxConcurrency) + `, "nodes": [
+			{"name": "a", "operator": "pool_test_probe"},
+			{"name": "b", "operator": "pool_test_probe"},
+			{"name": "c", "operator": "pool_test_probe"},
+			{"name": "d", "operator": "pool_test_probe"}
+		]}]`
+	}
+
+	tests := []struct {
+		name       string
+		config     string
+		maxWorkers int // 0 表示使用默认的全局上限
+		requests   int
+		wantPeak   int
+	}{
+		{name: "sequential", config: route(ModeSequential, 0), requests: 1, wantPeak: 1},
+		{name: "parallel without limits", config: route(ModeParallel, 0), requests: 1, wantPeak: 4},
+		{name: "route max_concurrency", config: route(ModeParallel, 2), requests: 1, wantPeak: 2},
+		// 路由上限按请求计算，两个请求可同时各占满
+		{name: "route limit per request", config: route(ModeParallel, 2), requests: 2, wantPeak: 4},
+		{name: "global worker limit", config: route(ModeParallel, 0), maxWorkers: 1, requests: 1, wantPeak: 1},
+		// 全局上限在所有请求间共享
+		{name: "global limit across requests", config: route(ModeParallel, 2), maxWorkers: 3, requests: 2, wantPeak: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe.reset()
+			s := newTestScheduler(t, tt.config)
+			if tt.maxWorkers > 0 {
+				s.SetMaxWorkers(tt.maxWorkers)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < tt.requests; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := s.Run(context.Background(), "/limits", ExecuteOptions{}); err != nil {
+						t.Errorf("Run: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			probe.mu.Lock()
+			defer probe.mu.Unlock()
+			if probe.peak != tt.wantPeak {
+				t.Errorf("peak concurrency = %d, want %d", probe.peak, tt.wantPeak)
+			}
+		})
+	}
+}
+
+func TestWaitingForSlotHonorsRouteTimeout(t *testing.T) {
+	registerTestOperator("pool_test_hold", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		time.Sleep(100 * time.Millisecond)
+		return ctx, &OperatorResult{}
+	})
+
+	s := newTestScheduler(t, `[{"server_name": "/wait", "mode": "parallel", "timeout": "30ms", "nodes": [
+		{"name": "a", "operator": "pool_test_hold"},
+		{"name": "b", "operator": "pool_test_hold"}
+	]}]`)
+	s.SetMaxWorkers(1)
+
+	start := time.Now()
+	output, err := s.Run(context.Background(), "/wait", ExecuteOptions{})
+	if err == nil {
+		t.Fatal("Run succeeded, want the route timeout")
+	}
+	// 第二个节点在等待槽位时随路由超时放弃，不等第一个节点结束
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("Run took %v, want it to give up waiting at the 30ms route timeout", elapsed)
+	}
+	if statusOf(err) != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", statusOf(err), http.StatusGatewayTimeout)
+	}
+	for _, result := range output.Results {
+		if result.Success {
+			t.Errorf("node %s succeeded after the route timed out", result.OperatorName)
+		}
+	}
+}
+
+func TestMaxConcurrencyConfig(t *testing.T) {
+	registerTestOperator("pool_test_op", dataOperator("ok"))
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "sequential route",
+			config:  `[{"server_name": "/a", "max_concurrency": 2, "nodes": [{"name": "pool_test_op"}]}]`,
+			wantErr: "max_concurrency requires mode parallel",
+		},
+		{
+			name:    "negative",
+			config:  `[{"server_name": "/a", "mode": "parallel", "max_concurrency": -1, "nodes": [{"name": "pool_test_op"}]}]`,
+			wantErr: "max_concurrency must not be negative",
+		},
+		{
+			name:    "unknown mode",
+			config:  `[{"server_name": "/a", "mode": "eventually", "nodes": [{"name": "pool_test_op"}]}]`,
+			wantErr: "unknown mode eventually",
+		},
+		{
+			name:   "parallel route",
+			config: `[{"server_name": "/a", "mode": "parallel", "max_concurrency": 2, "nodes": [{"name": "pool_test_op"}]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewScheduler().LoadConfig(writeConfig(t, tt.config))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadConfig error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package framework
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// WatchConfig 轮询配置文件的修改时间，文件变化后自动重载。
+// 新配置校验失败时保留旧计划并记录日志，ctx 取消后停止监听。
+func (s *Scheduler) WatchConfig(ctx context.Context, interval time.Duration) {
+	s.loadMu.Lock()
+	configPath := s.configPath
+	s.loadMu.Unlock()
+
+	lastModTime := time.Time{}
+	if info, err := os.Stat(configPath); err == nil {
+		lastModTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(configPath)
+			if err != nil {
+				log.Printf("Failed to stat config %s: %v", configPath, err)
+				continue
+			}
+			if !info.ModTime().After(lastModTime) {
+				continue
+			}
+			lastModTime = info.ModTime()
+
+			if err := s.Reload(); err != nil {
+				log.Printf("Config reload rejected, keeping previous plan: %v", err)
+				continue
+			}
+			log.Printf("Config reloaded from %s", configPath)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReloadKeepsPreviousPlanOnError(t *testing.T) {
+	registerTestOperator("reload_test_v1", dataOperator("v1"))
+	registerTestOperator("reload_test_v2", dataOperator("v2"))
+
+	path := writeConfig(t, `[{"server_name": "/r", "nodes": [{"name": "reload_test_v1"}]}]`)
+	s := NewScheduler()
+	if err := s.Reload(); err == nil {
+		t.Error("Reload before LoadConfig must fail")
+	}
+	if err := s.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		wantErr  string
+		wantBody string
+	}{
+		{name: "malformed json", config: `[{"server_name": `, wantErr: "failed to parse config", wantBody: "v1"},
+		{name: "unknown operator", config: `[{"server_name": "/r", "nodes": [{"name": "reload_test_missing"}]}]`, wantErr: "reload_test_missing", wantBody: "v1"},
+		{name: "cycle", config: `[{"server_name": "/r", "dependencies": {"reload_test_v1": "reload_test_v2", "reload_test_v2": "reload_test_v1"}}]`, wantErr: "cycle", wantBody: "v1"},
+		{name: "valid", config: `[{"server_name": "/r", "nodes": [{"name": "reload_test_v2"}]}]`, wantBody: "v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := s.Reload()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Reload: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+
+			output, err := s.Run(context.Background(), "/r", ExecuteOptions{})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if output.Body != tt.wantBody {
+				t.Errorf("body = %v, want %v", output.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	registerTestOperator("watch_test_v1", dataOperator("v1"))
+	registerTestOperator("watch_test_v2", dataOperator("v2"))
+
+	path := writeConfig(t, `[{"server_name": "/w", "nodes": [{"name": "watch_test_v1"}]}]`)
+	s := NewScheduler()
+	if err := s.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.WatchConfig(ctx, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// 修改时间前移，确保文件系统时间精度较粗时也能观察到变化
+	if err := os.WriteFile(path, []byte(`[{"server_name": "/w", "nodes": [{"name": "watch_test_v2"}]}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if output, err := s.Run(context.Background(), "/w", ExecuteOptions{}); err == nil && output.Body == "v2" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("config change was not reloaded")
+}
+
+func TestReloadKeepsNodeFactoryState(t *testing.T) {
+	// 计数保存在按路由与节点ID索引的注册表中，与限流器相同
+	var mu sync.Mutex
+	counters := make(map[string]*int)
+	RegisterOperatorFactory("reload_test_counter", NewNodeOperatorFactory("reload_test_counter",
+		func(serverName, nodeID string, config map[string]interface{}) (Operator, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			key := serverName + "/" + nodeID
+			if counters[key] == nil {
+				counters[key] = new(int)
+			}
+			count := counters[key]
+			return &testOperator{name: "reload_test_counter", execute: func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				*count++
+				return ctx, &OperatorResult{Data: *count}
+			}}, nil
+		}))
+
+	path := writeConfig(t, `[{"server_name": "/n", "nodes": [{"name": "counter", "factory": "reload_test_counter"}]}]`)
+	s := NewScheduler()
+	if err := s.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	for want := 1; want <= 3; want++ {
+		if want == 3 {
+			if err := s.Reload(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		output, err := s.Run(context.Background(), "/n", ExecuteOptions{})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if output.Body != want {
+			t.Errorf("run %d: body = %v, want %d", want, output.Body, want)
+		}
+	}
+	if _, ok := counters["/n/counter"]; !ok || len(counters) != 1 {
+		t.Errorf("factory was not keyed by route and node ID: %v", counters)
+	}
+}
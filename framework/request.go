@@ -0,0 +1,185 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// Request 与传输方式无关的请求，由 HTTP、RPC、后台任务、消息队列等来源适配而来。
+// 同一次执行中的全部算子（包括并行执行的算子）共享同一个 Request，算子不应修改它。
+type Request struct {
+	Method     string            // HTTP 方法；其他来源按语义选择，如读取用 GET、写入用 POST
+	Path       string            // 请求路径或来源标识（如消息主题）
+	Metadata   Metadata          // 请求元数据：HTTP 头、RPC metadata、消息属性
+	Query      url.Values        // 查询参数
+	Params     map[string]string // 路径参数
+	Body       []byte            // 请求体，由调度器按路由的 max_body_bytes 读取并校验大小
+	RemoteAddr string            // 调用方地址，如 "10.0.0.1:5423"，未知时为空
+	Identity   Identity          // 调用方身份
+
+	stream io.ReadCloser // 尚未读取的请求体，调度器执行前读入 Body
+	http   *http.Request // 来自 HTTP 时的原始请求
+}
+
+// Identity 调用方身份
+type Identity struct {
+	Token   string // Bearer 令牌，HTTP 与 RPC 来自 Authorization 元数据
+	Subject string // 由可信来源（后台任务、队列消费者）直接指定的用户ID，传输层适配器不会设置
+}
+
+// Metadata 请求元数据，键不区分大小写（按 HTTP 头的规范形式保存）
+type Metadata map[string][]string
+
+// Get 返回键的第一个值，不存在时返回空字符串
+func (m Metadata) Get(key string) string {
+	return http.Header(m).Get(key)
+}
+
+// Set 设置键的值，覆盖已有的值
+func (m Metadata) Set(key, value string) {
+	http.Header(m).Set(key, value)
+}
+
+// Values 返回键的全部值
+func (m Metadata) Values(key string) []string {
+	return http.Header(m).Values(key)
+}
+
+// NewRequest 创建请求，用于后台任务等非 HTTP 来源，调用方按需设置 Metadata、Query 与 Identity
+func NewRequest(method, path string, body []byte) *Request {
+	return &Request{
+		Method:   method,
+		Path:     path,
+		Metadata: make(Metadata),
+		Query:    make(url.Values),
+		Body:     body,
+	}
+}
+
+// NewMessageRequest 将队列消息适配为请求：主题作为 Path，消息属性作为 Metadata，消息体作为 Body
+func NewMessageRequest(topic string, attributes map[string]string, payload []byte) *Request {
+	return newMetadataRequest(http.MethodPost, topic, attributes, payload)
+}
+
+// NewRPCRequest 将 RPC 调用适配为请求，令牌从 metadata 的 authorization 中取出
+func NewRPCRequest(method, path string, metadata map[string]string, body []byte) *Request {
+	return newMetadataRequest(method, path, metadata, body)
+}
+
+func newMetadataRequest(method, path string, metadata map[string]string, body []byte) *Request {
+	req := NewRequest(method, path, body)
+	for key, value := range metadata {
+		req.Metadata.Set(key, value)
+	}
+	req.Identity.Token = bearerToken(req.Metadata.Get("Authorization"))
+	return req
+}
+
+// NewHTTPRequest 将 HTTP 请求适配为请求；请求体不在此读取，由调度器按路由的大小上限读取
+func NewHTTPRequest(r *http.Request) *Request {
+	req := &Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Metadata:   Metadata(r.Header.Clone()),
+		Query:      r.URL.Query(),
+		RemoteAddr: r.RemoteAddr,
+		Identity:   Identity{Token: bearerToken(r.Header.Get("Authorization"))},
+		http:       r,
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		req.stream = r.Body
+	}
+	if req.Metadata == nil {
+		req.Metadata = make(Metadata)
+	}
+	return req
+}
+
+// bearerToken 从 "Bearer <token>" 中取出令牌，格式不符时返回空字符串
+func bearerToken(authorization string) string {
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if token == authorization {
+		return ""
+	}
+	return token
+}
+
+// HTTP 兼容层：返回 HTTP 请求，供需要 Cookie、TLS 等 HTTP 特有信息的算子使用。
+// 请求来自 HTTP 时为原始请求的副本，否则按 Method、Path、Query、Metadata 构造；
+// 两种情况下 Body 都是缓冲请求体的新读取器，可以完整读取。
+func (r *Request) HTTP(ctx context.Context) *http.Request {
+	var req *http.Request
+	if r.http != nil {
+		req = r.http.Clone(ctx)
+	} else {
+		target := &url.URL{Path: r.Path, RawQuery: r.Query.Encode()}
+		req = (&http.Request{
+			Method:     r.Method,
+			URL:        target,
+			RequestURI: target.RequestURI(),
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header(r.Metadata).Clone(),
+			RemoteAddr: r.RemoteAddr,
+		}).WithContext(ctx)
+		if req.Header == nil {
+			req.Header = make(http.Header)
+		}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(r.Body))
+	req.ContentLength = int64(len(r.Body))
+	return req
+}
+
+// IsHTTP 请求是否来自 HTTP
+func (r *Request) IsHTTP() bool {
+	return r.http != nil
+}
+
+// loadBody 读取尚未读取的请求体，超过上限时返回413
+func (r *Request) loadBody(limit int64) error {
+	if r.stream != nil {
+		defer r.stream.Close()
+		body, err := io.ReadAll(io.LimitReader(r.stream, limit+1))
+		r.stream = nil
+		if err != nil {
+			return types.NewApiError("请求体读取失败", err.Error(), http.StatusBadRequest)
+		}
+		r.Body = body
+	}
+	if int64(len(r.Body)) > limit {
+		return types.NewApiError("请求体过大", fmt.Sprintf("Request body exceeds %d bytes", limit), http.StatusRequestEntityTooLarge)
+	}
+	return nil
+}
+
+// HTTPOperator 直接使用 *http.Request 的算子，通过 AdaptHTTPOperator 接入调度器
+type HTTPOperator interface {
+	Name() string
+	ExecuteHTTP(ctx context.Context, r *http.Request) (context.Context, *OperatorResult)
+}
+
+// AdaptHTTPOperator 兼容层：将 HTTPOperator 适配为 Operator，每次执行传入 Request.HTTP 构造的请求
+func AdaptHTTPOperator(op HTTPOperator) Operator {
+	return &httpOperator{inner: op}
+}
+
+type httpOperator struct {
+	inner HTTPOperator
+}
+
+func (o *httpOperator) Name() string {
+	return o.inner.Name()
+}
+
+func (o *httpOperator) Execute(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+	return o.inner.ExecuteHTTP(ctx, req.HTTP(ctx))
+}
@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestAdapters(t *testing.T) {
+	httpRequest := httptest.NewRequest(http.MethodPost, "/records?date=2024-01-02", strings.NewReader(`{"amount": 200}`))
+	httpRequest.RemoteAddr = "10.0.0.1:5423"
+	httpRequest.Header.Set("Authorization", "Bearer http-token")
+	httpRequest.Header.Set("X-Request-Id", "r1")
+
+	job := NewRequest(http.MethodGet, "/statistics", nil)
+	job.Identity.Subject = "u1"
+
+	tests := []struct {
+		name         string
+		req          *Request
+		wantMethod   string
+		wantPath     string
+		wantAddr     string
+		wantIdentity Identity
+		wantHTTP     bool
+		wantBody     string
+		wantMeta     map[string]string // 以非规范大小写的键读取
+	}{
+		{
+			name:         "http",
+			req:          NewHTTPRequest(httpRequest),
+			wantMethod:   http.MethodPost,
+			wantPath:     "/records",
+			wantAddr:     "10.0.0.1:5423",
+			wantIdentity: Identity{Token: "http-token"},
+			wantHTTP:     true,
+			wantBody:     `{"amount": 200}`,
+			wantMeta:     map[string]string{"x-request-id": "r1"},
+		},
+		{
+			name:         "rpc",
+			req:          NewRPCRequest(http.MethodGet, "/get_water_records", map[string]string{"authorization": "Bearer rpc-token", "x-request-id": "r2"}, []byte(`{}`)),
+			wantMethod:   http.MethodGet,
+			wantPath:     "/get_water_records",
+			wantIdentity: Identity{Token: "rpc-token"},
+			wantBody:     `{}`,
+			wantMeta:     map[string]string{"X-Request-Id": "r2"},
+		},
+		{
+			name:       "rpc without bearer scheme",
+			req:        NewRPCRequest(http.MethodGet, "/get_water_records", map[string]string{"authorization": "Basic dTpw"}, nil),
+			wantMethod: http.MethodGet,
+			wantPath:   "/get_water_records",
+			wantMeta:   map[string]string{"authorization": "Basic dTpw"},
+		},
+		{
+			name:       "message",
+			req:        NewMessageRequest("water.recorded", map[string]string{"event-id": "e1"}, []byte(`{"id": 7}`)),
+			wantMethod: http.MethodPost,
+			wantPath:   "water.recorded",
+			wantBody:   `{"id": 7}`,
+			wantMeta:   map[string]string{"Event-Id": "e1"},
+		},
+		{
+			name:         "background job",
+			req:          job,
+			wantMethod:   http.MethodGet,
+			wantPath:     "/statistics",
+			wantIdentity: Identity{Subject: "u1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if err := req.loadBody(DefaultMaxBodyBytes); err != nil {
+				t.Fatalf("loadBody: %v", err)
+			}
+			if req.Method != tt.wantMethod || req.Path != tt.wantPath || req.RemoteAddr != tt.wantAddr {
+				t.Errorf("request = %s %s from %q", req.Method, req.Path, req.RemoteAddr)
+			}
+			if req.Identity != tt.wantIdentity {
+				t.Errorf("Identity = %+v, want %+v", req.Identity, tt.wantIdentity)
+			}
+			if req.IsHTTP() != tt.wantHTTP {
+				t.Errorf("IsHTTP = %v, want %v", req.IsHTTP(), tt.wantHTTP)
+			}
+			if string(req.Body) != tt.wantBody {
+				t.Errorf("Body = %q, want %q", req.Body, tt.wantBody)
+			}
+			for key, want := range tt.wantMeta {
+				if got := req.Metadata.Get(key); got != want {
+					t.Errorf("Metadata.Get(%q) = %q, want %q", key, got, want)
+				}
+			}
+
+			// HTTP 兼容层每次返回可完整读取的请求体
+			for i := 0; i < 2; i++ {
+				converted := req.HTTP(context.Background())
+				body, err := io.ReadAll(converted.Body)
+				if err != nil || string(body) != tt.wantBody {
+					t.Errorf("HTTP() read %d: body = %q, %v", i, body, err)
+				}
+				if converted.Method != tt.wantMethod || converted.URL.Path != tt.wantPath {
+					t.Errorf("HTTP() = %s %s", converted.Method, converted.URL.Path)
+				}
+				for key, want := range tt.wantMeta {
+					if got := converted.Header.Get(key); got != want {
+						t.Errorf("HTTP() header %q = %q, want %q", key, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPRequestQueryAndParams(t *testing.T) {
+	registerTestOperator("request_test_echo", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Data: req.Params["id"] + "/" + req.Query.Get("date") + "/" + req.HTTP(ctx).URL.RawQuery}
+	})
+	s := newTestScheduler(t, `[{"server_name": "/record", "method": "GET", "path": "/records/{id}", "nodes": [{"name": "request_test_echo"}]}]`)
+
+	httpRequest := httptest.NewRequest(http.MethodGet, "/records/7?date=2024-01-02", nil)
+	route, params, err := s.Match(http.MethodGet, httpRequest.URL.Path)
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	req := NewHTTPRequest(httpRequest)
+	req.Params = params
+	output, err := s.Run(context.Background(), route, ExecuteOptions{Request: req})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.Body != "7/2024-01-02/date=2024-01-02" {
+		t.Errorf("body = %v", output.Body)
+	}
+}
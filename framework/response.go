@@ -0,0 +1,54 @@
+package framework
+
+import "net/http"
+
+// Response 算子对HTTP响应的控制，随 OperatorResult 返回。
+// 只有提供响应数据的节点的 Response 生效：路由配置了 response.from 时为该节点，
+// 未配置 response 时为最后一个实际执行的节点；response.compose 组合多个节点时不使用任何节点的 Response。
+type Response struct {
+	StatusCode  int            // 为 0 时使用 200
+	Header      http.Header    // 额外的响应头，如 Location、Cache-Control
+	Cookies     []*http.Cookie // 需要设置的 Cookie
+	ContentType string         // 为空时使用 application/json
+	Raw         bool           // 不使用 ApiResponse 包装：Body 非空时原样输出，否则直接输出响应数据的 JSON
+	Body        []byte         // 原始响应体，如 CSV 内容
+}
+
+// NewResponse 创建指定状态码的响应控制
+func NewResponse(statusCode int) *Response {
+	return &Response{StatusCode: statusCode, Header: make(http.Header)}
+}
+
+// SetHeader 设置响应头，返回自身便于链式调用
+func (r *Response) SetHeader(key, value string) *Response {
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	r.Header.Set(key, value)
+	return r
+}
+
+// SetCookie 追加 Cookie，返回自身便于链式调用
+func (r *Response) SetCookie(cookie *http.Cookie) *Response {
+	r.Cookies = append(r.Cookies, cookie)
+	return r
+}
+
+// RawBody 设置不经 ApiResponse 包装的原始响应体
+func (r *Response) RawBody(contentType string, body []byte) *Response {
+	r.Raw = true
+	r.ContentType = contentType
+	r.Body = body
+	return r
+}
+
+// clone 复制响应控制，避免调用方修改算子返回的结果
+func (r *Response) clone() *Response {
+	copied := *r
+	copied.Header = r.Header.Clone()
+	if copied.Header == nil {
+		copied.Header = make(http.Header)
+	}
+	copied.Cookies = append([]*http.Cookie(nil), r.Cookies...)
+	return &copied
+}
@@ -0,0 +1,88 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestResponseControlFromSourceNode(t *testing.T) {
+	responding := func(name string, resp func() *Response) {
+		registerTestOperator(name, func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+			return ctx, &OperatorResult{Data: name, Response: resp()}
+		})
+	}
+	responding("resp_test_created", func() *Response {
+		return NewResponse(http.StatusCreated).SetHeader("Location", "/records/1").SetCookie(&http.Cookie{Name: "session", Value: "s"})
+	})
+	responding("resp_test_csv", func() *Response {
+		return NewResponse(0).SetHeader("X-Export", "1").RawBody("text/csv", []byte("a,b\n"))
+	})
+	responding("resp_test_none", func() *Response { return nil })
+
+	tests := []struct {
+		name       string
+		route      string
+		wantStatus int
+		wantHeader map[string]string
+		wantRaw    bool
+		wantNil    bool
+	}{
+		{
+			name:       "final node only",
+			route:      `{"server_name": "/r", "dependencies": {"resp_test_created": "resp_test_csv"}}`,
+			wantHeader: map[string]string{"X-Export": "1", "Location": ""},
+			wantRaw:    true,
+		},
+		{
+			name:       "response.from node only",
+			route:      `{"server_name": "/r", "dependencies": {"resp_test_created": "resp_test_csv"}, "response": {"from": "resp_test_created"}}`,
+			wantStatus: http.StatusCreated,
+			wantHeader: map[string]string{"Location": "/records/1", "X-Export": ""},
+		},
+		{
+			name:    "compose uses no node",
+			route:   `{"server_name": "/r", "dependencies": {"resp_test_created": "resp_test_csv"}, "response": {"compose": {"a": "resp_test_created", "b": "resp_test_csv"}}}`,
+			wantNil: true,
+		},
+		{
+			name:    "final node without response control",
+			route:   `{"server_name": "/r", "dependencies": {"resp_test_created": "resp_test_none"}}`,
+			wantNil: true,
+		},
+		{
+			name:       "skipped final node falls back to the last executed node",
+			route:      `{"server_name": "/r", "nodes": [{"name": "resp_test_created", "downstream": [{"node": "resp_test_csv", "when": "false"}]}, {"name": "resp_test_csv"}]}`,
+			wantStatus: http.StatusCreated,
+			wantHeader: map[string]string{"Location": "/records/1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, "["+tt.route+"]")
+			output, err := s.Run(context.Background(), "/r", ExecuteOptions{})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			resp := output.Response
+			if tt.wantNil {
+				if resp != nil {
+					t.Errorf("response = %+v, want nil", resp)
+				}
+				return
+			}
+			if resp == nil {
+				t.Fatal("response is nil")
+			}
+			if resp.StatusCode != tt.wantStatus || resp.Raw != tt.wantRaw {
+				t.Errorf("status = %d, raw = %v; want %d, %v", resp.StatusCode, resp.Raw, tt.wantStatus, tt.wantRaw)
+			}
+			for key, want := range tt.wantHeader {
+				if got := resp.Header.Get(key); got != want {
+					t.Errorf("header %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
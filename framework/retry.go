@@ -0,0 +1,130 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// IdempotentOperator 可选接口：声明算子重复执行不会产生额外副作用，允许按策略重试
+type IdempotentOperator interface {
+	Idempotent() bool
+}
+
+// RetryPolicy 节点的重试策略
+type RetryPolicy struct {
+	MaxAttempts     int      `json:"max_attempts"`     // 最大尝试次数（含首次）
+	InitialBackoff  Duration `json:"initial_backoff"`  // 首次重试前的等待时间
+	MaxBackoff      Duration `json:"max_backoff"`      // 等待时间上限
+	Multiplier      float64  `json:"multiplier"`       // 指数退避倍数，默认 2
+	Jitter          float64  `json:"jitter"`           // 抖动比例 0~1，如 0.2 表示 ±20%
+	RetryableStatus []int    `json:"retryable_status"` // 可重试的 ApiError 状态码，与 retryable_errors 都未配置时默认 502/503
+	RetryableErrors []string `json:"retryable_errors"` // 可重试的错误类型：timeout / internal
+	Idempotent      bool     `json:"idempotent"`       // 显式声明该节点可安全重试（用于未实现 IdempotentOperator 的算子）
+}
+
+const (
+	RetryableTimeout  = "timeout"  // 算子超时
+	RetryableInternal = "internal" // 非 ApiError 的普通错误
+)
+
+var defaultRetryableStatus = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+// validate 校验策略并填充默认值，非幂等算子未显式声明时拒绝重试配置
+func (p *RetryPolicy) validate(nodeID string, op Operator) error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("retry max_attempts for node %s must be at least 1", nodeID)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("retry jitter for node %s must be between 0 and 1", nodeID)
+	}
+	for _, kind := range p.RetryableErrors {
+		if kind != RetryableTimeout && kind != RetryableInternal {
+			return fmt.Errorf("unknown retryable error type %q for node %s", kind, nodeID)
+		}
+	}
+
+	if p.MaxAttempts > 1 && !p.Idempotent {
+		idempotent, ok := asOperator[IdempotentOperator](op)
+		if !ok || !idempotent.Idempotent() {
+			return fmt.Errorf("node %s is not idempotent; set retry.idempotent to allow retries", nodeID)
+		}
+	}
+
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	// 只配置了 retryable_errors 时不再附加默认状态码
+	if len(p.RetryableStatus) == 0 && len(p.RetryableErrors) == 0 {
+		p.RetryableStatus = defaultRetryableStatus
+	}
+	return nil
+}
+
+// retryable 判断错误是否满足重试条件
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return p.allowsError(RetryableTimeout)
+	}
+
+	var apiErr *types.ApiError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusGatewayTimeout && p.allowsError(RetryableTimeout) {
+			return true
+		}
+		for _, status := range p.RetryableStatus {
+			if apiErr.StatusCode == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	return p.allowsError(RetryableInternal)
+}
+
+func (p *RetryPolicy) allowsError(kind string) bool {
+	for _, k := range p.RetryableErrors {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 计算第 attempt 次失败后的等待时间（指数退避 + 抖动）
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff.Duration) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxBackoff.Duration > 0 && delay > float64(p.MaxBackoff.Duration) {
+		delay = float64(p.MaxBackoff.Duration)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// sleepContext 等待指定时间，上下文结束时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,158 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// idempotentOperator 声明幂等的测试算子
+type idempotentOperator struct{ testOperator }
+
+func (o *idempotentOperator) Idempotent() bool { return true }
+
+func TestRetryPolicyValidate(t *testing.T) {
+	plain := &testOperator{name: "retry_test_plain"}
+	idempotent := &idempotentOperator{testOperator{name: "retry_test_idempotent"}}
+
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		op         Operator
+		wantErr    string
+		wantStatus []int
+	}{
+		{name: "defaults", policy: RetryPolicy{MaxAttempts: 3}, op: idempotent, wantStatus: defaultRetryableStatus},
+		{name: "only retryable_errors", policy: RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{RetryableTimeout}}, op: idempotent},
+		{name: "explicit status", policy: RetryPolicy{MaxAttempts: 3, RetryableStatus: []int{429}}, op: idempotent, wantStatus: []int{429}},
+		{name: "single attempt needs no idempotency", policy: RetryPolicy{MaxAttempts: 1}, op: plain, wantStatus: defaultRetryableStatus},
+		{name: "explicit idempotent flag", policy: RetryPolicy{MaxAttempts: 2, Idempotent: true}, op: plain, wantStatus: defaultRetryableStatus},
+		{name: "not idempotent", policy: RetryPolicy{MaxAttempts: 2}, op: plain, wantErr: "not idempotent"},
+		{name: "no attempts", policy: RetryPolicy{}, op: idempotent, wantErr: "at least 1"},
+		{name: "jitter out of range", policy: RetryPolicy{MaxAttempts: 2, Jitter: 1.5}, op: idempotent, wantErr: "jitter"},
+		{name: "unknown error type", policy: RetryPolicy{MaxAttempts: 2, RetryableErrors: []string{"network"}}, op: idempotent, wantErr: "unknown retryable error type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			err := policy.validate("node", tt.op)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			if !reflect.DeepEqual(policy.RetryableStatus, tt.wantStatus) {
+				t.Errorf("retryable_status = %v, want %v", policy.RetryableStatus, tt.wantStatus)
+			}
+			if policy.Multiplier != 2 {
+				t.Errorf("multiplier = %v, want default 2", policy.Multiplier)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	apiErr := func(status int) error { return types.NewApiError("错误", "error", status) }
+
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		err    error
+		want   bool
+	}{
+		{name: "default status", policy: RetryPolicy{RetryableStatus: defaultRetryableStatus}, err: apiErr(http.StatusServiceUnavailable), want: true},
+		{name: "status not listed", policy: RetryPolicy{RetryableStatus: defaultRetryableStatus}, err: apiErr(http.StatusBadRequest)},
+		{name: "wrapped status", policy: RetryPolicy{RetryableStatus: []int{429}}, err: fmt.Errorf("call failed: %w", apiErr(429)), want: true},
+		{name: "deadline with timeout", policy: RetryPolicy{RetryableErrors: []string{RetryableTimeout}}, err: context.DeadlineExceeded, want: true},
+		{name: "deadline without timeout", policy: RetryPolicy{RetryableStatus: defaultRetryableStatus}, err: context.DeadlineExceeded},
+		{name: "504 with timeout", policy: RetryPolicy{RetryableErrors: []string{RetryableTimeout}}, err: apiErr(http.StatusGatewayTimeout), want: true},
+		{name: "timeout only does not retry 503", policy: RetryPolicy{RetryableErrors: []string{RetryableTimeout}}, err: apiErr(http.StatusServiceUnavailable)},
+		{name: "plain error with internal", policy: RetryPolicy{RetryableErrors: []string{RetryableInternal}}, err: errors.New("boom"), want: true},
+		{name: "plain error without internal", policy: RetryPolicy{RetryableStatus: defaultRetryableStatus}, err: errors.New("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: Duration{100 * time.Millisecond},
+		MaxBackoff:     Duration{300 * time.Millisecond},
+		Multiplier:     2,
+	}
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond, // 400ms 被 max_backoff 截断
+		5: 300 * time.Millisecond,
+	} {
+		if got := policy.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 80*time.Millisecond || got > 120*time.Millisecond {
+			t.Fatalf("backoff with jitter = %v, want within ±20%% of 100ms", got)
+		}
+	}
+}
+
+func TestRetryExecution(t *testing.T) {
+	unavailable := types.NewApiError("服务暂不可用", "Service unavailable", http.StatusServiceUnavailable)
+	badRequest := types.NewApiError("参数错误", "Bad request", http.StatusBadRequest)
+
+	tests := []struct {
+		name         string
+		failures     int // 前 failures 次尝试失败
+		err          error
+		retry        string
+		wantAttempts int
+		wantSuccess  bool
+	}{
+		{name: "succeeds after retry", failures: 2, err: unavailable, retry: `{"max_attempts": 3, "idempotent": true}`, wantAttempts: 3, wantSuccess: true},
+		{name: "exhausted", failures: 5, err: unavailable, retry: `{"max_attempts": 3, "idempotent": true}`, wantAttempts: 3},
+		{name: "not retryable", failures: 5, err: badRequest, retry: `{"max_attempts": 3, "idempotent": true}`, wantAttempts: 1},
+		{name: "retryable_errors only skips default status", failures: 5, err: unavailable, retry: `{"max_attempts": 3, "idempotent": true, "retryable_errors": ["timeout"]}`, wantAttempts: 1},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			opName := fmt.Sprintf("retry_test_exec_%d", i)
+			registerTestOperator(opName, func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+				if int(calls.Add(1)) <= tt.failures {
+					return ctx, &OperatorResult{Error: tt.err}
+				}
+				return ctx, &OperatorResult{Data: "ok"}
+			})
+			s := newTestScheduler(t, `[{"server_name": "/retry", "nodes": [{"name": "`+opName+`", "retry": `+tt.retry+`}]}]`)
+
+			output, _ := s.Run(context.Background(), "/retry", ExecuteOptions{})
+			result := output.Results[0]
+			if result.Attempts != tt.wantAttempts || result.Success != tt.wantSuccess {
+				t.Errorf("attempts = %d, success = %v, want %d, %v", result.Attempts, result.Success, tt.wantAttempts, tt.wantSuccess)
+			}
+			if int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("operator ran %d times, want %d", calls.Load(), tt.wantAttempts)
+			}
+		})
+	}
+}
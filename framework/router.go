@@ -0,0 +1,184 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrRouteNotFound    = errors.New("route not found")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+// MethodNotAllowedError 路径能匹配但方法不符，Allowed 为该路径可用的方法，用于 405 响应的 Allow 头。
+// errors.Is(err, ErrMethodNotAllowed) 成立
+type MethodNotAllowedError struct {
+	Allowed []string
+}
+
+func (e *MethodNotAllowedError) Error() string {
+	return ErrMethodNotAllowed.Error()
+}
+
+func (e *MethodNotAllowedError) Is(target error) bool {
+	return target == ErrMethodNotAllowed
+}
+
+type pathParamsKey struct{}
+
+// Router 按 HTTP 方法 + 路径模式匹配路由，路径模式支持 {name} 形式的命名参数
+type Router struct {
+	routes []*routeEntry
+}
+
+type routeEntry struct {
+	method     string // 为空表示匹配任意方法
+	pattern    string
+	segments   []string
+	serverName string
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Add 注册路由，同一方法下重复的路径模式会返回错误
+func (rt *Router) Add(method, pattern, serverName string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("route pattern %q must start with /", pattern)
+	}
+
+	segments := splitPath(pattern)
+	for _, seg := range segments {
+		if isParamSegment(seg) && len(seg) == 2 {
+			return fmt.Errorf("route pattern %q has an empty parameter name", pattern)
+		}
+	}
+
+	method = strings.ToUpper(method)
+	for _, existing := range rt.routes {
+		if existing.method == method && samePattern(existing.segments, segments) {
+			return fmt.Errorf("duplicate route %s %s (%s and %s)", method, pattern, existing.serverName, serverName)
+		}
+	}
+
+	rt.routes = append(rt.routes, &routeEntry{
+		method:     method,
+		pattern:    pattern,
+		segments:   segments,
+		serverName: serverName,
+	})
+	return nil
+}
+
+// Match 返回匹配的 server_name 及路径参数。
+// 路径能匹配但方法不符时返回 *MethodNotAllowedError，静态段优先于参数段匹配。
+func (rt *Router) Match(method, path string) (string, map[string]string, error) {
+	segments := splitPath(path)
+	method = strings.ToUpper(method)
+
+	var best *routeEntry
+	var bestParams map[string]string
+	bestScore := -1
+	var allowed []string
+
+	for _, route := range rt.routes {
+		params, score, ok := matchSegments(route.segments, segments)
+		if !ok {
+			continue
+		}
+
+		if route.method != "" && route.method != method {
+			if !contains(allowed, route.method) {
+				allowed = append(allowed, route.method)
+			}
+			continue
+		}
+		// 显式声明方法的路由优先于任意方法路由
+		if route.method != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestParams, bestScore = route, params, score
+		}
+	}
+
+	if best != nil {
+		return best.serverName, bestParams, nil
+	}
+	if len(allowed) > 0 {
+		sort.Strings(allowed)
+		return "", nil, &MethodNotAllowedError{Allowed: allowed}
+	}
+	return "", nil, ErrRouteNotFound
+}
+
+// matchSegments 匹配路径段，score 越高表示静态段越多、匹配越精确
+func matchSegments(pattern, path []string) (map[string]string, int, bool) {
+	if len(pattern) != len(path) {
+		return nil, 0, false
+	}
+
+	params := make(map[string]string)
+	score := 0
+	for i, seg := range pattern {
+		if isParamSegment(seg) {
+			if path[i] == "" {
+				return nil, 0, false
+			}
+			params[seg[1:len(seg)-1]] = path[i]
+			continue
+		}
+		if seg != path[i] {
+			return nil, 0, false
+		}
+		score += 2
+	}
+	return params, score, true
+}
+
+func samePattern(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if isParamSegment(a[i]) && isParamSegment(b[i]) {
+			continue
+		}
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isParamSegment(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// WithPathParams 将路径参数放入上下文
+func WithPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, pathParamsKey{}, params)
+}
+
+// PathParams 获取全部路径参数
+func PathParams(ctx context.Context) map[string]string {
+	params, _ := ctx.Value(pathParamsKey{}).(map[string]string)
+	return params
+}
+
+// PathParam 获取单个路径参数，不存在时返回空字符串
+func PathParam(ctx context.Context, name string) string {
+	return PathParams(ctx)[name]
+}
@@ -0,0 +1,116 @@
+package framework
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouterMatch(t *testing.T) {
+	rt := NewRouter()
+	for _, r := range []struct{ method, pattern, serverName string }{
+		{"GET", "/reminder_config", "/get_reminder_config"},
+		{"PUT", "/reminder_config", "/update_reminder_config"},
+		{"GET", "/records/{id}", "/get_drinking_record"},
+		{"DELETE", "/records/{id}", "/delete_drinking_record"},
+		{"GET", "/records/latest", "/latest_drinking_record"},
+		{"", "/auth", "/auth"},
+	} {
+		if err := rt.Add(r.method, r.pattern, r.serverName); err != nil {
+			t.Fatalf("Add(%s %s): %v", r.method, r.pattern, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantServer  string
+		wantParams  map[string]string
+		wantAllowed []string // 非空表示期望 405
+		wantMissing bool
+	}{
+		{name: "get", method: "GET", path: "/reminder_config", wantServer: "/get_reminder_config"},
+		{name: "put", method: "PUT", path: "/reminder_config", wantServer: "/update_reminder_config"},
+		{name: "param", method: "GET", path: "/records/42", wantServer: "/get_drinking_record", wantParams: map[string]string{"id": "42"}},
+		{name: "static wins", method: "GET", path: "/records/latest", wantServer: "/latest_drinking_record"},
+		{name: "any method", method: "PATCH", path: "/auth", wantServer: "/auth"},
+		{name: "method not allowed", method: "POST", path: "/reminder_config", wantAllowed: []string{"GET", "PUT"}},
+		{name: "allowed across patterns", method: "POST", path: "/records/latest", wantAllowed: []string{"DELETE", "GET"}},
+		{name: "not found", method: "GET", path: "/missing", wantMissing: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverName, params, err := rt.Match(tt.method, tt.path)
+			switch {
+			case tt.wantMissing:
+				if !errors.Is(err, ErrRouteNotFound) {
+					t.Fatalf("error = %v, want ErrRouteNotFound", err)
+				}
+			case tt.wantAllowed != nil:
+				var methodErr *MethodNotAllowedError
+				if !errors.As(err, &methodErr) || !errors.Is(err, ErrMethodNotAllowed) {
+					t.Fatalf("error = %v, want MethodNotAllowedError", err)
+				}
+				if !reflect.DeepEqual(methodErr.Allowed, tt.wantAllowed) {
+					t.Errorf("allowed = %v, want %v", methodErr.Allowed, tt.wantAllowed)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Match: %v", err)
+				}
+				if serverName != tt.wantServer {
+					t.Errorf("server_name = %q, want %q", serverName, tt.wantServer)
+				}
+				if tt.wantParams != nil && !reflect.DeepEqual(params, tt.wantParams) {
+					t.Errorf("params = %v, want %v", params, tt.wantParams)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteAliases(t *testing.T) {
+	registerTestOperator("alias_test_config", dataOperator("config"))
+	s := newTestScheduler(t, `[
+	  {"server_name": "/get_config", "method": "GET", "path": "/config", "aliases": [{"path": "/get_config"}], "nodes": [{"name": "alias_test_config"}]},
+	  {"server_name": "/update_config", "method": "PUT", "path": "/config", "aliases": [{"method": "POST", "path": "/update_config"}], "nodes": [{"name": "alias_test_config"}]}
+	]`)
+
+	tests := []struct {
+		method      string
+		path        string
+		wantServer  string
+		wantAllowed []string
+	}{
+		{method: "GET", path: "/config", wantServer: "/get_config"},
+		{method: "GET", path: "/get_config", wantServer: "/get_config"},
+		{method: "PUT", path: "/config", wantServer: "/update_config"},
+		{method: "POST", path: "/update_config", wantServer: "/update_config"},
+		{method: "PUT", path: "/update_config", wantAllowed: []string{"POST"}},
+		{method: "POST", path: "/config", wantAllowed: []string{"GET", "PUT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			serverName, _, err := s.Match(tt.method, tt.path)
+			if tt.wantAllowed != nil {
+				var methodErr *MethodNotAllowedError
+				if !errors.As(err, &methodErr) || !reflect.DeepEqual(methodErr.Allowed, tt.wantAllowed) {
+					t.Fatalf("error = %v, want 405 allowing %v", err, tt.wantAllowed)
+				}
+				return
+			}
+			if err != nil || serverName != tt.wantServer {
+				t.Errorf("Match = %q, %v, want %q", serverName, err, tt.wantServer)
+			}
+		})
+	}
+
+	doc := s.OpenAPI(OpenAPIInfo{Title: "test"})
+	if op := doc.Paths["/config"]["get"]; op == nil || op.Deprecated {
+		t.Errorf("GET /config = %+v, want a current operation", op)
+	}
+	if op := doc.Paths["/update_config"]["post"]; op == nil || !op.Deprecated || op.OperationID != "update_config_alias1" {
+		t.Errorf("POST /update_config = %+v, want a deprecated alias", op)
+	}
+}
@@ -2,94 +2,319 @@ package framework
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
 )
 
 type ExecutionResult struct {
 	OperatorName string
 	Success      bool
+	Skipped      bool // 所有入边均未激活（条件不成立或上游被跳过），节点未执行
 	Error        error
-	Duration     int64
+	Duration     int64       // 耗时（毫秒），含重试与退避等待
 	Data         interface{} // 存储算子执行结果的数据
+	Attempts     int         // 实际执行次数（含重试）
+	Response     *Response   // 算子设置的HTTP响应控制
+
+	Compensated       bool  // 路由失败后已成功补偿
+	CompensationError error // 补偿失败的原因
 }
 
 type ExecuteOptions struct {
 	Parallel bool
-	Request  *http.Request
-	Response http.ResponseWriter
+	Request  *Request // 为空时使用空请求
 }
 
 type Scheduler struct {
-	operators      map[string]Operator
-	dependencies   map[string]map[string][]string // server_name -> dependencies
-	executionOrder map[string][][]string          // server_name -> execution order (grouped by level)
+	configPath string
+	loadMu     sync.Mutex                 // 串行化配置加载，避免并发重载互相覆盖
+	plans      atomic.Pointer[routeTable] // 当前生效的执行计划，整体原子替换
+	panics     *panicTracker              // 算子 panic 统计与隔离状态，不随配置重载清空
+	workers    *workerPool                // 并行模式下所有请求共享的执行槽位
+}
+
+// routeTable 一份完整的路由配置：路由器与 server_name -> execution plan
+type routeTable struct {
+	router *Router
+	plans  map[string]*executionPlan
+}
+
+// executionPlan 预计算的路由执行计划
+type executionPlan struct {
+	serverName     string
+	method         string                   // 路由匹配的请求方法，为空时匹配任意方法
+	path           string                   // 路由匹配的路径模式
+	aliases        []RouteAlias             // 附加入口
+	timeout        time.Duration            // 整个路由的超时，0 表示不限制
+	nodeTimeouts   map[string]time.Duration // 节点ID -> 单个算子的超时
+	retryPolicies  map[string]*RetryPolicy  // 节点ID -> 重试策略
+	operators      map[string]Operator      // 图中各节点对应的算子实例
+	levels         [][]string               // 按层级分组的执行顺序
+	nodeLevels     map[string]int           // 节点ID -> 所在层级
+	upstream       map[string][]string      // 算子 -> 上游算子（汇合点需要等待的全部前驱）
+	conditions     map[edgeKey]*Condition   // 条件边 -> 编译后的条件
+	response       *ResponseConfig          // 响应数据来源，为空时使用最后一个执行的节点
+	downstream     map[string][]string      // 算子 -> 下游算子
+	quarantine     *QuarantinePolicy        // 算子 panic 隔离策略，为空时只统计不隔离
+	parallel       bool                     // 路由配置为 parallel 模式
+	maxConcurrency int                      // parallel 模式下单个请求同时执行的节点上限，0 表示不限制
+	transactional  bool                     // 所有节点共享一个数据库事务
+	maxBodyBytes   int64                    // 请求体大小上限
 }
 
 func NewScheduler() *Scheduler {
-	return &Scheduler{
-		dependencies:   make(map[string]map[string][]string),
-		executionOrder: make(map[string][][]string),
-	}
+	s := &Scheduler{panics: newPanicTracker(), workers: newWorkerPool(DefaultMaxWorkers)}
+	s.plans.Store(&routeTable{router: NewRouter(), plans: map[string]*executionPlan{}})
+	return s
 }
 
+// LoadConfig 加载配置并生成全部路由的执行计划。
+// 只有全部路由都校验通过后才会替换当前计划，否则保留旧计划并返回错误。
 func (s *Scheduler) LoadConfig(configPath string) error {
-	data, err := os.ReadFile(configPath)
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	file, err := readConfigFile(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+		return err
+	}
+
+	table := &routeTable{
+		router: NewRouter(),
+		plans:  make(map[string]*executionPlan, len(file.Routes)),
+	}
+	for _, config := range file.Routes {
+		if _, exists := table.plans[config.ServerName]; exists {
+			return fmt.Errorf("duplicate server_name %s", config.ServerName)
+		}
+		if err := config.addRoutes(table.router); err != nil {
+			return err
+		}
+
+		expanded, _, err := file.expandRoute(config)
+		if err != nil {
+			return fmt.Errorf("invalid include for %s: %w", config.ServerName, err)
+		}
+		plan, err := s.compileRoute(expanded)
+		if err != nil {
+			return err
+		}
+		table.plans[config.ServerName] = plan
+	}
+
+	s.configPath = configPath
+	s.plans.Store(table)
+	return nil
+}
+
+// compileRoute 校验单个路由配置并生成执行计划
+func (s *Scheduler) compileRoute(config RouteConfig) (*executionPlan, error) {
+	graph, err := config.buildGraph()
+	if err != nil {
+		return nil, fmt.Errorf("invalid graph for %s: %w", config.ServerName, err)
+	}
+
+	plan, err := s.precomputeExecutionOrder(config.ServerName, graph, config.nodeConfigs(graph))
+	if err != nil {
+		return nil, fmt.Errorf("failed to precompute execution order for %s: %w", config.ServerName, err)
+	}
+	plan.serverName = config.ServerName
+	plan.method = config.Method
+	plan.path = config.routePath()
+	plan.aliases = config.routeAliases()
+	plan.timeout = config.Timeout.Duration
+
+	if err := config.validateMode(); err != nil {
+		return nil, fmt.Errorf("invalid mode for %s: %w", config.ServerName, err)
+	}
+	plan.parallel = config.Mode == ModeParallel
+	plan.maxConcurrency = config.MaxConcurrency
+
+	if err := config.validateTransactional(); err != nil {
+		return nil, fmt.Errorf("invalid transactional for %s: %w", config.ServerName, err)
+	}
+	plan.transactional = config.Transactional
+
+	if err := config.validateBodyLimit(); err != nil {
+		return nil, fmt.Errorf("invalid max_body_bytes for %s: %w", config.ServerName, err)
+	}
+	plan.maxBodyBytes = config.MaxBodyBytes
+	if plan.maxBodyBytes == 0 {
+		plan.maxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	if err := config.Quarantine.validate(); err != nil {
+		return nil, fmt.Errorf("invalid quarantine for %s: %w", config.ServerName, err)
+	}
+	plan.quarantine = config.Quarantine
+
+	if err := config.Response.validate(graph); err != nil {
+		return nil, fmt.Errorf("invalid response for %s: %w", config.ServerName, err)
 	}
+	plan.response = config.Response
+	return plan, nil
+}
+
+// Reload 重新加载上一次 LoadConfig 使用的配置文件
+func (s *Scheduler) Reload() error {
+	s.loadMu.Lock()
+	configPath := s.configPath
+	s.loadMu.Unlock()
 
-	var configs []struct {
-		ServerName   string            `json:"server_name"`
-		Dependencies map[string]string `json:"dependencies"`
+	if configPath == "" {
+		return errors.New("no config loaded")
 	}
+	return s.LoadConfig(configPath)
+}
 
-	if err := json.Unmarshal(data, &configs); err != nil {
-		return fmt.Errorf("failed to parse config: %w", err)
+// SetMaxWorkers 设置全局并行执行槽位数量，应在开始处理请求前调用
+func (s *Scheduler) SetMaxWorkers(n int) {
+	if n < 1 {
+		n = 1
 	}
+	s.workers = newWorkerPool(n)
+}
+
+// PanicStats 返回各路由节点的 panic 次数与隔离截止时间
+func (s *Scheduler) PanicStats() []PanicStat {
+	return s.panics.snapshot()
+}
+
+// Match 按请求方法和路径查找路由，返回 server_name 与路径参数
+func (s *Scheduler) Match(method, path string) (string, map[string]string, error) {
+	return s.plans.Load().router.Match(method, path)
+}
 
-	for _, config := range configs {
-		// 将字符串依赖关系转换为字符串数组
-		dependencies := make(map[string][]string)
-		for op, dep := range config.Dependencies {
-			dependencies[op] = []string{dep}
+func (s *Scheduler) precomputeExecutionOrder(serverName string, graph *routeGraph, nodes map[string]NodeConfig) (*executionPlan, error) {
+	dependencies := graph.downstream
+	operators := make(map[string]Operator, len(dependencies))
+	nodeTimeouts := make(map[string]time.Duration)
+	retryPolicies := make(map[string]*RetryPolicy)
+	for nodeID := range dependencies {
+		op, err := s.resolveOperator(serverName, nodes[nodeID])
+		if err != nil {
+			return nil, err
 		}
+		operators[nodeID] = op
 
-		s.dependencies[config.ServerName] = dependencies
-		if err := s.precomputeExecutionOrder(config.ServerName); err != nil {
-			return fmt.Errorf("failed to precompute execution order for %s: %w", config.ServerName, err)
+		if policy := nodes[nodeID].Retry; policy != nil {
+			policy := *policy
+			if err := policy.validate(nodeID, op); err != nil {
+				return nil, err
+			}
+			retryPolicies[nodeID] = &policy
+		}
+
+		if timeout := nodes[nodeID].Timeout.Duration; timeout > 0 {
+			nodeTimeouts[nodeID] = timeout
 		}
 	}
 
-	return nil
+	levels, err := s.topologicalSort(dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeLevels := make(map[string]int, len(dependencies))
+	for i, level := range levels {
+		for _, nodeID := range level {
+			nodeLevels[nodeID] = i
+		}
+	}
+
+	upstream := make(map[string][]string, len(dependencies))
+	for op, deps := range dependencies {
+		for _, dep := range deps {
+			upstream[dep] = append(upstream[dep], op)
+		}
+	}
+	for _, ups := range upstream {
+		sort.Strings(ups)
+	}
+
+	conditions := make(map[edgeKey]*Condition, len(graph.conditions))
+	for edge, source := range graph.conditions {
+		cond, err := CompileCondition(source)
+		if err != nil {
+			return nil, fmt.Errorf("edge %s -> %s: %w", edge.from, edge.to, err)
+		}
+		for _, ref := range cond.referencedNodes() {
+			if _, exists := dependencies[ref]; !exists {
+				return nil, fmt.Errorf("edge %s -> %s: condition references unknown node %s", edge.from, edge.to, ref)
+			}
+		}
+		conditions[edge] = cond
+	}
+
+	return &executionPlan{
+		conditions:    conditions,
+		nodeTimeouts:  nodeTimeouts,
+		retryPolicies: retryPolicies,
+		operators:     operators,
+		levels:        levels,
+		nodeLevels:    nodeLevels,
+		upstream:      upstream,
+		downstream:    dependencies,
+	}, nil
+}
+
+// operatorName 节点对应算子的名称，用于追踪与时间线
+func (p *executionPlan) operatorName(nodeID string) string {
+	if op, exists := p.operators[nodeID]; exists {
+		return op.Name()
+	}
+	return nodeID
 }
 
-func (s *Scheduler) precomputeExecutionOrder(serverName string) error {
-	if s.operators == nil {
-		s.operators = make(map[string]Operator)
+// resolveOperator 为节点创建算子：配置了工厂时每个节点创建独立实例，否则使用全局注册表中的单例；
+// 节点配置了装饰器时返回包装后的算子
+func (s *Scheduler) resolveOperator(serverName string, node NodeConfig) (Operator, error) {
+	op, err := s.baseOperator(serverName, node)
+	if err != nil {
+		return nil, err
 	}
+	return decorate(op, node)
+}
 
-	dependencies := s.dependencies[serverName]
-	for opName := range dependencies {
-		// 使用全局算子注册表
-		if op, err := GetOperator(opName); err == nil {
-			s.operators[opName] = op
+func (s *Scheduler) baseOperator(serverName string, node NodeConfig) (Operator, error) {
+	if node.Factory != "" {
+		factory, exists := lookupOperatorFactory(node.Factory)
+		if !exists {
+			return nil, fmt.Errorf("operator factory %s not found for node %s", node.Factory, node.Name)
+		}
+		// 按节点保存状态的工厂需要路由与节点ID，配置重载后同一节点沿用原有状态
+		var op Operator
+		var err error
+		if nodeFactory, ok := factory.(NodeOperatorFactory); ok {
+			op, err = nodeFactory.CreateNode(serverName, node.Name, node.Config)
 		} else {
-			return fmt.Errorf("operator %s not found in registry", opName)
+			op, err = factory.Create(node.Config)
 		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create node %s from factory %s: %w", node.Name, node.Factory, err)
+		}
+		return op, nil
 	}
 
-	executionOrder, err := s.topologicalSort(dependencies)
+	opName := node.Operator
+	if opName == "" {
+		opName = node.Name
+	}
+	// 使用全局算子注册表
+	op, err := GetOperator(opName)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("operator %s not found in registry", opName)
 	}
-
-	s.executionOrder[serverName] = executionOrder
-	return nil
+	return op, nil
 }
 
 func (s *Scheduler) topologicalSort(dependencies map[string][]string) ([][]string, error) {
@@ -115,7 +340,8 @@ func (s *Scheduler) topologicalSort(dependencies map[string][]string) ([][]strin
 
 	result := [][]string{}
 	for len(queue) > 0 {
-		// 当前层级的所有算子可以并行执行
+		// 当前层级的所有算子可以并行执行，排序保证执行顺序稳定
+		sort.Strings(queue)
 		currentLevel := make([]string, len(queue))
 		copy(currentLevel, queue)
 		result = append(result, currentLevel)
@@ -140,7 +366,7 @@ func (s *Scheduler) topologicalSort(dependencies map[string][]string) ([][]strin
 		processedCount += len(level)
 	}
 
-	if len(result) == 0 || processedCount != len(dependencies) {
+	if len(result) == 0 || processedCount != len(inDegree) {
 		return nil, errors.New("cycle detected in dependencies")
 	}
 
@@ -148,38 +374,105 @@ func (s *Scheduler) topologicalSort(dependencies map[string][]string) ([][]strin
 }
 
 func (s *Scheduler) Execute(ctx context.Context, serverName string, opts ExecuteOptions) ([]ExecutionResult, error) {
-	executionOrder, exists := s.executionOrder[serverName]
+	output, err := s.Run(ctx, serverName, opts)
+	if output == nil {
+		return nil, err
+	}
+	return output.Results, err
+}
+
+// Run 执行路由并按路由的 response 配置组装响应数据
+func (s *Scheduler) Run(ctx context.Context, serverName string, opts ExecuteOptions) (*ExecutionOutput, error) {
+	// 整个请求使用同一份计划，重载不会影响进行中的请求
+	plan, exists := s.plans.Load().plans[serverName]
 	if !exists {
 		return nil, fmt.Errorf("execution order not found for server: %s", serverName)
 	}
 
-	if opts.Parallel {
-		return s.executeSmartParallel(ctx, executionOrder, opts)
+	start := time.Now()
+	routesInFlight.Inc(serverName)
+	defer routesInFlight.Dec(serverName)
+
+	if plan.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, plan.timeout)
+		defer cancel()
+	}
+
+	// 请求状态贯穿整个执行过程，调用方可预先放入上下文以读取执行后的状态
+	if StateFromContext(ctx) == nil {
+		ctx = WithState(ctx, NewState())
+	}
+
+	if opts.Request == nil {
+		opts.Request = NewRequest("", serverName, nil)
+	}
+	if opts.Request.Params != nil {
+		ctx = WithPathParams(ctx, opts.Request.Params)
+	}
+
+	// 每次执行一个根 span，节点 span 挂在其下
+	exec := newExecution(plan, opts)
+	exec.span = startRequestSpan(opts.Request, "route "+serverName)
+	exec.span.SetAttribute("route", serverName)
+	if opts.Request.IsHTTP() {
+		exec.span.SetAttribute("http.method", opts.Request.Method)
+	}
+	ctx = context.WithValue(withExecution(ctx, exec), spanKey{}, exec.span)
+
+	// 请求体只读取一次，算子通过 Request.Body 或 DecodeBody 读取缓冲内容
+	var results []ExecutionResult
+	err := opts.Request.loadBody(plan.maxBodyBytes)
+	if err == nil {
+		results, err = s.execute(ctx, exec)
 	}
-	return s.executeSequential(ctx, executionOrder, opts)
+
+	// 路由失败时撤销已成功节点的副作用；事务路由的写入已随回滚撤销，不再补偿
+	if err != nil && !plan.transactional {
+		s.compensate(ctx, exec, results)
+	}
+	exec.span.End(err)
+
+	output := &ExecutionOutput{
+		Results:  results,
+		TraceID:  exec.span.TraceID,
+		Timeline: exec.timeline(results),
+	}
+	if err == nil {
+		output.Body = exec.buildResponse(results)
+		output.Response = exec.responseControl(results)
+	}
+	observeRoute(serverName, output, err, time.Since(start))
+	return output, err
 }
 
-func (s *Scheduler) executeSequential(ctx context.Context, executionOrder [][]string, opts ExecuteOptions) ([]ExecutionResult, error) {
+// execute 按路由配置选择执行方式，调用方也可通过 opts.Parallel 强制并行
+func (s *Scheduler) execute(ctx context.Context, exec *execution) ([]ExecutionResult, error) {
+	parallel := exec.opts.Parallel || exec.plan.parallel
+	switch {
+	case exec.plan.transactional && parallel:
+		return nil, types.NewApiError("事务路由不支持并行执行", "Transactional route "+exec.plan.serverName+" cannot run in parallel mode", http.StatusInternalServerError)
+	case exec.plan.transactional:
+		return s.executeTransactional(ctx, exec)
+	case parallel:
+		return s.executeSmartParallel(ctx, exec)
+	default:
+		return s.executeSequential(ctx, exec)
+	}
+}
+
+func (s *Scheduler) executeSequential(ctx context.Context, exec *execution) ([]ExecutionResult, error) {
 	results := []ExecutionResult{}
-	for _, level := range executionOrder {
+	for _, level := range exec.plan.levels {
 		for _, opName := range level {
-			op, exists := s.operators[opName]
-			if !exists {
-				results = append(results, ExecutionResult{
-					OperatorName: opName,
-					Success:      false,
-					Error:        fmt.Errorf("operator not found"),
-				})
+			if !exec.shouldRun(ctx, opName) {
+				results = append(results, skippedResult(opName))
 				continue
 			}
 
-			newCtx, result := op.Execute(ctx, opts.Request)
-			results = append(results, ExecutionResult{
-				OperatorName: opName,
-				Success:      result.Error == nil,
-				Error:        result.Error,
-				Data:         result.Data,
-			})
+			newCtx, result := s.runNode(ctx, ctx, exec, opName)
+			results = append(results, result)
+			exec.record(result)
 
 			if result.Error != nil {
 				log.Printf("Operator %s failed: %v", opName, result.Error)
@@ -192,121 +485,238 @@ func (s *Scheduler) executeSequential(ctx context.Context, executionOrder [][]st
 	return results, nil
 }
 
-func (s *Scheduler) executeSmartParallel(ctx context.Context, executionOrder [][]string, opts ExecuteOptions) ([]ExecutionResult, error) {
+func (s *Scheduler) executeSmartParallel(ctx context.Context, exec *execution) ([]ExecutionResult, error) {
 	results := []ExecutionResult{}
+	state := StateFromContext(ctx)
 
-	for _, level := range executionOrder {
-		levelResults := make([]ExecutionResult, len(level))
-		levelCtx := ctx // 每个层级共享同一个初始上下文
-
-		// 并行执行当前层级的所有算子
-		type opResult struct {
-			idx      int
-			result   ExecutionResult
-			newCtx   context.Context
-			hasError bool
-		}
+	// 路由级并发上限在单个请求内生效，全局上限由 workers 在所有请求间共享
+	workers := s.workers
+	var routeSlots chan struct{}
+	if exec.plan.maxConcurrency > 0 {
+		routeSlots = make(chan struct{}, exec.plan.maxConcurrency)
+	}
 
-		resultCh := make(chan opResult, len(level))
+	for _, level := range exec.plan.levels {
+		levelResults := make([]ExecutionResult, len(level))
+		// 每个算子写入独立的状态分支，层级结束后按节点顺序合并
+		forks := make([]*State, len(level))
+
+		resultCh := make(chan struct {
+			idx    int
+			result ExecutionResult
+		}, len(level))
+		launched := 0
+		var levelErrors []error
 
 		for i, opName := range level {
-			go func(idx int, name string) {
-				op, exists := s.operators[name]
-				if !exists {
-					resultCh <- opResult{
-						idx: idx,
-						result: ExecutionResult{
-							OperatorName: name,
-							Success:      false,
-							Error:        fmt.Errorf("operator not found"),
-						},
-						hasError: true,
-					}
-					return
-				}
+			if !exec.shouldRun(ctx, opName) {
+				levelResults[i] = skippedResult(opName)
+				continue
+			}
 
-				newCtx, operatorResult := op.Execute(levelCtx, opts.Request)
-				execResult := ExecutionResult{
-					OperatorName: name,
-					Success:      operatorResult.Error == nil,
-					Error:        operatorResult.Error,
-					Data:         operatorResult.Data,
-				}
+			// 先获取槽位再启动 goroutine，槽位不足时在此等待
+			if err := workers.acquire(ctx, routeSlots); err != nil {
+				levelResults[i] = ExecutionResult{OperatorName: opName, Error: contextError(opName, err)}
+				levelErrors = append(levelErrors, levelResults[i].Error)
+				continue
+			}
 
-				resultCh <- opResult{
-					idx:      idx,
-					result:   execResult,
-					newCtx:   newCtx,
-					hasError: operatorResult.Error != nil,
-				}
-			}(i, opName)
+			forks[i] = state.fork()
+			nodeSlot := workers.newSlot(routeSlots)
+			inputCtx := withSlot(WithState(ctx, forks[i]), nodeSlot)
+
+			launched++
+			go func(idx int, name string, inputCtx context.Context) {
+				defer nodeSlot.done()
+				// 并行模式下算子之间通过请求状态共享数据，算子返回的上下文不再向下传递
+				_, result := s.runNode(ctx, inputCtx, exec, name)
+				resultCh <- struct {
+					idx    int
+					result ExecutionResult
+				}{idx: idx, result: result}
+			}(i, opName, inputCtx)
 		}
 
 		// 收集当前层级的所有结果
-		var levelErrors []error
-		successfulCtxs := []context.Context{}
-
-		for i := 0; i < len(level); i++ {
+		for i := 0; i < launched; i++ {
 			res := <-resultCh
 			levelResults[res.idx] = res.result
+			exec.record(res.result)
 
-			if res.hasError {
+			if res.result.Error != nil {
 				levelErrors = append(levelErrors, res.result.Error)
 				log.Printf("Operator %s failed: %v", res.result.OperatorName, res.result.Error)
-			} else if res.newCtx != nil {
-				successfulCtxs = append(successfulCtxs, res.newCtx)
 			}
 		}
 
-		// 检查当前层级是否有错误
+		// 检查当前层级是否有错误，有错误时跳过剩余层级
 		if len(levelErrors) > 0 {
-			return append(results, levelResults...), fmt.Errorf("parallel execution failed at level: %v", levelErrors)
+			return append(results, levelResults...), fmt.Errorf("parallel execution failed at level: %w", errors.Join(levelErrors...))
+		}
+
+		// 合并成功算子的状态写入，同一键写入不同值时整个层级失败
+		var mergeIDs []string
+		var mergeForks []*State
+		for i, fork := range forks {
+			if fork != nil {
+				mergeIDs = append(mergeIDs, level[i])
+				mergeForks = append(mergeForks, fork)
+			}
+		}
+		if err := state.merge(mergeIDs, mergeForks); err != nil {
+			log.Printf("State merge failed: %v", err)
+			conflict := types.NewApiError("状态合并冲突", err.Error(), http.StatusInternalServerError)
+			return append(results, levelResults...), fmt.Errorf("parallel execution failed at level: %w", conflict)
 		}
 
 		// 收集当前层级的结果
 		results = append(results, levelResults...)
-
-		// 更新上下文：合并所有成功算子的上下文
-		if len(successfulCtxs) > 0 {
-			ctx = s.mergeContexts(successfulCtxs)
-		}
 	}
 
 	return results, nil
 }
 
-// mergeContexts 合并多个上下文，优先保留后出现的值
-func (s *Scheduler) mergeContexts(ctxs []context.Context) context.Context {
-	if len(ctxs) == 0 {
-		return context.Background()
+// runNode 执行单个节点，按节点的重试策略重试可重试的失败。
+// routeCtx 携带请求级别的取消与路由超时，inputCtx 携带上游写入的值；
+// 返回的上下文重新挂回 routeCtx，避免下游继承已结束的节点超时。
+// 每个节点记录一个 span，结果中的耗时包含全部重试。
+func (s *Scheduler) runNode(routeCtx, inputCtx context.Context, exec *execution, nodeID string) (context.Context, ExecutionResult) {
+	plan, opts := exec.plan, exec.opts
+	span := exec.startNodeSpan(nodeID)
+	newCtx, result := s.runNodeAttempts(routeCtx, context.WithValue(inputCtx, spanKey{}, span), plan, nodeID, opts)
+
+	span.SetAttribute("attempts", result.Attempts)
+	var apiErr *types.ApiError
+	if errors.As(result.Error, &apiErr) {
+		span.SetAttribute("http.status_code", apiErr.StatusCode)
 	}
+	span.End(result.Error)
+	result.Duration = span.Duration().Milliseconds()
+	observeNode(plan.serverName, result, span.Duration())
 
-	merged := ctxs[0]
-	for i := 1; i < len(ctxs); i++ {
-		merged = s.mergeTwoContexts(merged, ctxs[i])
+	// 失败时下游不会执行，返回未挂载节点 span 的输入上下文
+	if newCtx == nil || result.Error != nil {
+		return inputCtx, result
 	}
-	return merged
+	return newCtx, result
 }
 
-// mergeTwoContexts 合并两个上下文
-func (s *Scheduler) mergeTwoContexts(ctx1, ctx2 context.Context) context.Context {
-	merged := context.Background()
+// runNodeAttempts 按重试策略执行节点的全部尝试
+func (s *Scheduler) runNodeAttempts(routeCtx, inputCtx context.Context, plan *executionPlan, nodeID string, opts ExecuteOptions) (context.Context, ExecutionResult) {
+	op, exists := plan.operators[nodeID]
+	if !exists {
+		return inputCtx, ExecutionResult{
+			OperatorName: nodeID,
+			Success:      false,
+			Error:        fmt.Errorf("operator not found"),
+		}
+	}
 
-	// 从ctx1复制所有值
-	if ctx1 != nil {
-		if user := ctx1.Value("user"); user != nil {
-			merged = context.WithValue(merged, "user", user)
+	// 隔离期内的算子不再执行，直接快速失败
+	if s.panics.quarantined(plan.serverName, nodeID, time.Now()) {
+		return inputCtx, ExecutionResult{
+			OperatorName: nodeID,
+			Success:      false,
+			Error:        quarantinedError(nodeID),
 		}
-		// 可以添加其他需要合并的上下文键值
 	}
 
-	// 从ctx2复制所有值（会覆盖ctx1中的相同键）
-	if ctx2 != nil {
-		if user := ctx2.Value("user"); user != nil {
-			merged = context.WithValue(merged, "user", user)
+	policy := plan.retryPolicies[nodeID]
+	maxAttempts := 1
+	if policy != nil {
+		maxAttempts = policy.MaxAttempts
+	}
+
+	var newCtx context.Context
+	var result *OperatorResult
+	attempts := 0
+	for attempts < maxAttempts {
+		// 上一个节点或上一次尝试结束时请求可能已被取消或超时，直接结束
+		if err := routeCtx.Err(); err != nil {
+			result = &OperatorResult{Error: contextError(nodeID, err)}
+			break
+		}
+
+		attempts++
+		var panicked bool
+		newCtx, result, panicked = s.attemptNode(inputCtx, op, plan.nodeTimeouts[nodeID], nodeID, opts)
+		if panicked {
+			// panic 说明算子存在缺陷，重试没有意义
+			s.panics.record(plan.serverName, nodeID, plan.quarantine, time.Now())
+			notifyPanic(plan.serverName, nodeID)
+			break
+		}
+		if result.Error == nil || attempts >= maxAttempts || !policy.retryable(result.Error) {
+			break
+		}
+
+		log.Printf("Operator %s attempt %d failed, retrying: %v", nodeID, attempts, result.Error)
+		if !sleepContext(routeCtx, policy.backoff(attempts)) {
+			continue // 下一轮循环按上下文错误结束
 		}
-		// 可以添加其他需要合并的上下文键值
 	}
 
-	return merged
+	if result.Error != nil || newCtx == nil {
+		newCtx = inputCtx
+	} else {
+		newCtx = withValuesFrom(routeCtx, newCtx)
+	}
+
+	return newCtx, ExecutionResult{
+		OperatorName: nodeID,
+		Success:      result.Error == nil,
+		Error:        result.Error,
+		Data:         result.Data,
+		Attempts:     attempts,
+		Response:     result.Response,
+	}
+}
+
+// attemptNode 执行一次算子，节点超时只作用于本次尝试。
+// 算子 panic 时恢复并转换为500错误，第三个返回值表示本次尝试是否 panic。
+func (s *Scheduler) attemptNode(inputCtx context.Context, op Operator, timeout time.Duration, nodeID string, opts ExecuteOptions) (context.Context, *OperatorResult, bool) {
+	nodeCtx := inputCtx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		nodeCtx, cancel = context.WithTimeout(inputCtx, timeout)
+		defer cancel()
+	}
+
+	type executeResult struct {
+		ctx      context.Context
+		result   *OperatorResult
+		panicked bool
+	}
+	done := make(chan executeResult, 1)
+	// 算子 goroutine 持有节点的槽位直到返回，超时放弃后也不会超出并发上限
+	nodeSlot := slotFromContext(inputCtx)
+	nodeSlot.hold()
+	go func() {
+		defer nodeSlot.done()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("Operator %s panicked: %v\n%s", nodeID, recovered, debug.Stack())
+				done <- executeResult{result: &OperatorResult{Error: panicError(nodeID)}, panicked: true}
+			}
+		}()
+		newCtx, result := op.Execute(nodeCtx, opts.Request)
+		done <- executeResult{ctx: newCtx, result: result}
+	}()
+
+	// 不配合取消的算子在超时后被放弃，其结果直接丢弃。
+	// 事务路由中算子可能仍在使用事务，需等待其返回后才能回滚，结果同样按超时处理
+	select {
+	case res := <-done:
+		if res.result == nil {
+			res.result = &OperatorResult{}
+		}
+		return res.ctx, res.result, res.panicked
+	case <-nodeCtx.Done():
+		if _, inTx := Get(inputCtx, TxKey); inTx {
+			if res := <-done; res.panicked {
+				return nil, res.result, true
+			}
+		}
+		return nil, &OperatorResult{Error: contextError(nodeID, nodeCtx.Err())}, false
+	}
 }
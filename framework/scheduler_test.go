@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testOperator 以函数实现的测试算子
+type testOperator struct {
+	name    string
+	execute func(ctx context.Context, req *Request) (context.Context, *OperatorResult)
+}
+
+func (o *testOperator) Name() string { return o.name }
+
+func (o *testOperator) Execute(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+	return o.execute(ctx, req)
+}
+
+// registerTestOperator 以 name 注册测试算子
+func registerTestOperator(name string, execute func(ctx context.Context, req *Request) (context.Context, *OperatorResult)) {
+	RegisterOperator(name, &testOperator{name: name, execute: execute})
+}
+
+// dataOperator 直接返回 data 的算子
+func dataOperator(data interface{}) func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+	return func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Data: data}
+	}
+}
+
+// writeConfig 将调度配置写入临时文件并返回路径
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scheduler_config.json")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTestScheduler 按 config 加载路由
+func newTestScheduler(t *testing.T, config string) *Scheduler {
+	t.Helper()
+	s := NewScheduler()
+	if err := s.LoadConfig(writeConfig(t, config)); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return s
+}
+
+func TestDiamondWithSkippedBranch(t *testing.T) {
+	registerTestOperator("diamond_root", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Data: map[string]string{"branch": req.Query.Get("branch")}}
+	})
+	registerTestOperator("diamond_left", dataOperator("left"))
+	registerTestOperator("diamond_right", dataOperator("right"))
+	// join 通过 Output 读取实际执行的上游
+	registerTestOperator("diamond_join", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		var ran []string
+		for _, up := range []string{"left", "right"} {
+			if value, ok := Output[string](ctx, up); ok {
+				ran = append(ran, value)
+			}
+		}
+		return ctx, &OperatorResult{Data: strings.Join(ran, "+")}
+	})
+
+	const nodes = `"nodes": [
+		{"name": "root", "operator": "diamond_root", "downstream": [
+			{"node": "left", "when": "data.root.branch == 'left' || data.root.branch == 'both'"},
+			{"node": "right", "when": "data.root.branch == 'right' || data.root.branch == 'both'"}
+		]},
+		{"name": "left", "operator": "diamond_left", "downstream": "join"},
+		{"name": "right", "operator": "diamond_right", "downstream": "join"},
+		{"name": "join", "operator": "diamond_join"}
+	]`
+
+	tests := []struct {
+		branch      string
+		wantSkipped string // 被跳过的节点
+		wantBody    interface{}
+	}{
+		{branch: "both", wantSkipped: "", wantBody: "left+right"},
+		{branch: "left", wantSkipped: "right", wantBody: "left"},
+		{branch: "right", wantSkipped: "left", wantBody: "right"},
+		// 两条分支都未激活时汇合节点也被跳过，响应取最后一个实际执行的节点
+		{branch: "none", wantSkipped: "left,right,join", wantBody: map[string]string{"branch": "none"}},
+	}
+	for _, mode := range []string{ModeSequential, ModeParallel} {
+		s := newTestScheduler(t, `[{"server_name": "/diamond", "mode": "`+mode+`", `+nodes+`}]`)
+
+		for _, tt := range tests {
+			t.Run(mode+"/"+tt.branch, func(t *testing.T) {
+				request := NewRequest(http.MethodGet, "/diamond", nil)
+				request.Query.Set("branch", tt.branch)
+				output, err := s.Run(context.Background(), "/diamond", ExecuteOptions{Request: request})
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+
+				order := make([]string, 0, len(output.Results))
+				var skipped []string
+				for _, result := range output.Results {
+					order = append(order, result.OperatorName)
+					switch {
+					case result.Skipped:
+						skipped = append(skipped, result.OperatorName)
+					case !result.Success:
+						t.Errorf("node %s failed: %v", result.OperatorName, result.Error)
+					}
+				}
+				// 每个节点恰好出现一次，汇合节点在两条分支之后
+				if got := strings.Join(order, ","); got != "root,left,right,join" {
+					t.Errorf("result order = %s", got)
+				}
+				if got := strings.Join(skipped, ","); got != tt.wantSkipped {
+					t.Errorf("skipped = %q, want %q", got, tt.wantSkipped)
+				}
+				if !reflect.DeepEqual(output.Body, tt.wantBody) {
+					t.Errorf("body = %#v, want %#v", output.Body, tt.wantBody)
+				}
+			})
+		}
+	}
+}
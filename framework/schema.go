@@ -0,0 +1,251 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema（OpenAPI 3.1 使用的 2020-12 版本的子集），
+// 既用于生成 OpenAPI 文档，也用于校验请求
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+
+	pattern *regexp.Regexp // Compile 预编译的 Pattern
+}
+
+// OperatorSchema 算子声明的请求与响应类型，字段为对应 Go 类型的零值，如 LoginRequest{}
+type OperatorSchema struct {
+	Summary  string      // 接口说明
+	Request  interface{} // 请求体类型，nil 表示不读取请求体
+	Query    interface{} // 查询参数结构体，参数名取 json 标签
+	Response interface{} // 成功时 OperatorResult.Data 的类型
+	Errors   []int       // 可能返回的 ApiError 状态码
+	Auth     bool        // 需要 Bearer 令牌
+}
+
+// SchemaProvider 可选接口：算子按请求方法声明请求与响应类型，用于生成 OpenAPI 文档与请求校验。
+// 算子不处理该方法时返回 nil
+type SchemaProvider interface {
+	Schema(method string) *OperatorSchema
+}
+
+// SchemaOf 由 Go 类型生成 JSON Schema。
+//
+// 字段名取 json 标签，json:"-" 的字段被忽略；schema 标签声明约束，多个约束以逗号分隔：
+//
+//	required、format=date、enum=day|week、min=1、max=10、exclusiveMin=0、exclusiveMax=100、
+//	minLength=1、maxLength=64、pattern=^[a-z]+$、minItems=1、maxItems=10
+//
+// pattern 中不能包含逗号。v 为 *Schema 时直接返回，便于算子声明配置中的 schema。
+func SchemaOf(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if schema, ok := v.(*Schema); ok {
+		return schema
+	}
+	return schemaOfType(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOfType visiting 记录正在展开的结构体，递归类型在第二次出现时不再展开
+func schemaOfType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOfType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOfType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		addStructFields(schema, t, visiting)
+		sort.Strings(schema.Required)
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+// addStructFields 添加结构体字段，匿名嵌入的结构体字段提升到外层
+func addStructFields(schema *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(schema, embedded, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaOfType(field.Type, visiting)
+		required, err := property.applyTag(field.Tag.Get("schema"))
+		if err != nil {
+			panic(fmt.Sprintf("invalid schema tag on %s.%s: %v", t.Name(), field.Name, err))
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// jsonFieldName 返回 json 标签中的字段名，json:"-" 时 skip 为 true
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+// applyTag 应用 schema 标签中的约束，返回字段是否必填
+func (s *Schema) applyTag(tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	required := false
+	for _, item := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		var err error
+		switch key {
+		case "required":
+			required = true
+		case "format":
+			s.Format = value
+		case "pattern":
+			s.Pattern = value
+		case "enum":
+			for _, option := range strings.Split(value, "|") {
+				s.Enum = append(s.Enum, s.enumValue(option))
+			}
+		case "min":
+			s.Minimum, err = parseFloat(value)
+		case "max":
+			s.Maximum, err = parseFloat(value)
+		case "exclusiveMin":
+			s.ExclusiveMinimum, err = parseFloat(value)
+		case "exclusiveMax":
+			s.ExclusiveMaximum, err = parseFloat(value)
+		case "minLength":
+			s.MinLength, err = parseInt(value)
+		case "maxLength":
+			s.MaxLength, err = parseInt(value)
+		case "minItems":
+			s.MinItems, err = parseInt(value)
+		case "maxItems":
+			s.MaxItems, err = parseInt(value)
+		default:
+			err = fmt.Errorf("unknown constraint %s", key)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return required, nil
+}
+
+// enumValue 数值字段的枚举值按数字比较
+func (s *Schema) enumValue(option string) interface{} {
+	if s.Type == "integer" || s.Type == "number" {
+		if n, err := strconv.ParseFloat(option, 64); err == nil {
+			return n
+		}
+	}
+	return option
+}
+
+func parseFloat(value string) (*float64, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func parseInt(value string) (*int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// SchemaFor 返回算子（包括被装饰器包装的算子）对请求方法声明的 schema，未声明时返回 nil
+func SchemaFor(op Operator, method string) *OperatorSchema {
+	provider, ok := asOperator[SchemaProvider](op)
+	if !ok {
+		return nil
+	}
+	return provider.Schema(method)
+}
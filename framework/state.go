@@ -0,0 +1,133 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Key 请求状态中的带类型键，名称在整个应用内必须唯一
+type Key[T any] struct {
+	name string
+}
+
+// NewKey 创建带类型的状态键
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// State 单个请求内算子之间共享的状态，并发安全。
+//
+// 并行层级中每个算子拿到自己的分支（fork），写入只对自己可见；
+// 层级结束后按节点顺序合并回父状态，不同算子对同一键写入不同的值视为冲突。
+type State struct {
+	mu     sync.RWMutex
+	parent *State
+	values map[string]interface{}
+}
+
+type stateKey struct{}
+
+// NewState 创建空的请求状态
+func NewState() *State {
+	return &State{values: make(map[string]interface{})}
+}
+
+// WithState 将请求状态放入上下文
+func WithState(ctx context.Context, st *State) context.Context {
+	return context.WithValue(ctx, stateKey{}, st)
+}
+
+// StateFromContext 获取上下文中的请求状态，不存在时返回 nil
+func StateFromContext(ctx context.Context) *State {
+	st, _ := ctx.Value(stateKey{}).(*State)
+	return st
+}
+
+// Get 从上下文的请求状态中读取值
+func Get[T any](ctx context.Context, key Key[T]) (T, bool) {
+	var zero T
+	st := StateFromContext(ctx)
+	if st == nil {
+		return zero, false
+	}
+	raw, ok := st.lookup(key.name)
+	if !ok {
+		return zero, false
+	}
+	value, ok := raw.(T)
+	return value, ok
+}
+
+// Set 向上下文的请求状态写入值，上下文中没有状态时返回错误
+func Set[T any](ctx context.Context, key Key[T], value T) error {
+	st := StateFromContext(ctx)
+	if st == nil {
+		return fmt.Errorf("no request state in context for key %s", key.name)
+	}
+	st.set(key.name, value)
+	return nil
+}
+
+func (s *State) lookup(name string) (interface{}, bool) {
+	for st := s; st != nil; st = st.parent {
+		st.mu.RLock()
+		value, ok := st.values[name]
+		st.mu.RUnlock()
+		if ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (s *State) set(name string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
+
+// fork 创建读取穿透到当前状态、写入相互隔离的分支
+func (s *State) fork() *State {
+	return &State{parent: s, values: make(map[string]interface{})}
+}
+
+// merge 按给定顺序将分支的写入合并回当前状态。
+// 两个分支对同一键写入不相等的值时返回冲突错误，且不会合并任何写入。
+func (s *State) merge(nodeIDs []string, forks []*State) error {
+	merged := make(map[string]interface{})
+	writers := make(map[string]string)
+
+	for i, fork := range forks {
+		fork.mu.RLock()
+		names := make([]string, 0, len(fork.values))
+		for name := range fork.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := fork.values[name]
+			if existing, ok := merged[name]; ok && !reflect.DeepEqual(existing, value) {
+				fork.mu.RUnlock()
+				return fmt.Errorf("state key %s written with conflicting values by %s and %s", name, writers[name], nodeIDs[i])
+			}
+			merged[name] = value
+			writers[name] = nodeIDs[i]
+		}
+		fork.mu.RUnlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, value := range merged {
+		s.values[name] = value
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+func TestStateForkIsolation(t *testing.T) {
+	parent := NewState()
+	parent.set("shared", "parent")
+
+	fork := parent.fork()
+	fork.set("own", 1)
+
+	if value, ok := fork.lookup("shared"); !ok || value != "parent" {
+		t.Errorf("fork lookup shared = %v, %v; want read-through to parent", value, ok)
+	}
+	if _, ok := parent.lookup("own"); ok {
+		t.Error("fork write is visible in parent before merge")
+	}
+}
+
+func TestStateMerge(t *testing.T) {
+	type user struct{ ID string }
+
+	tests := []struct {
+		name    string
+		writes  []map[string]interface{} // 每个分支的写入
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "disjoint keys",
+			writes: []map[string]interface{}{{"a": 1}, {"b": 2}},
+			want:   map[string]interface{}{"a": 1, "b": 2},
+		},
+		{
+			name:   "same key with deeply equal values",
+			writes: []map[string]interface{}{{"user": &user{ID: "u1"}}, {"user": &user{ID: "u1"}}},
+			want:   map[string]interface{}{"user": &user{ID: "u1"}},
+		},
+		{
+			name:    "same key with conflicting values",
+			writes:  []map[string]interface{}{{"a": 1, "user": "u1"}, {"b": 2}, {"user": "u2"}},
+			wantErr: "state key user written with conflicting values by n0 and n2",
+		},
+		{
+			name:    "conflicting pointer targets",
+			writes:  []map[string]interface{}{{"user": &user{ID: "u1"}}, {"user": &user{ID: "u2"}}},
+			wantErr: "state key user written with conflicting values by n0 and n1",
+		},
+		{
+			name:   "no forks",
+			writes: nil,
+			want:   map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := NewState()
+			var ids []string
+			var forks []*State
+			for i, writes := range tt.writes {
+				fork := parent.fork()
+				for name, value := range writes {
+					fork.set(name, value)
+				}
+				ids = append(ids, fmt.Sprintf("n%d", i))
+				forks = append(forks, fork)
+			}
+
+			err := parent.merge(ids, forks)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				// 冲突时不合并任何写入
+				if len(parent.values) != 0 {
+					t.Errorf("parent has %v after a failed merge", parent.values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("merge: %v", err)
+			}
+			if len(parent.values) != len(tt.want) {
+				t.Fatalf("parent = %v, want %v", parent.values, tt.want)
+			}
+			for name, want := range tt.want {
+				if got, _ := parent.lookup(name); !valuesEqual(toConditionValue(got), toConditionValue(want)) {
+					t.Errorf("%s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelStateConflictFailsRoute(t *testing.T) {
+	key := NewKey[string]("state_test.user")
+	writer := func(value string) func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+			if err := Set(ctx, key, value); err != nil {
+				return ctx, &OperatorResult{Error: err}
+			}
+			return ctx, &OperatorResult{}
+		}
+	}
+	registerTestOperator("state_test_u1", writer("u1"))
+	registerTestOperator("state_test_u1_again", writer("u1"))
+	registerTestOperator("state_test_u2", writer("u2"))
+
+	tests := []struct {
+		name    string
+		nodes   string
+		wantErr bool
+	}{
+		{name: "equal writes merge", nodes: `{"name": "state_test_u1"}, {"name": "state_test_u1_again"}`},
+		{name: "conflicting writes fail", nodes: `{"name": "state_test_u1"}, {"name": "state_test_u2"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, `[{"server_name": "/state", "mode": "parallel", "nodes": [`+tt.nodes+`]}]`)
+			state := NewState()
+			_, err := s.Run(WithState(context.Background(), state), "/state", ExecuteOptions{})
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+				if got, _ := Get(WithState(context.Background(), state), key); got != "u1" {
+					t.Errorf("merged value = %q, want u1", got)
+				}
+				return
+			}
+			var apiErr *types.ApiError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || !strings.Contains(err.Error(), "conflicting values") {
+				t.Errorf("err = %v, want 500 state merge conflict", err)
+			}
+		})
+	}
+}
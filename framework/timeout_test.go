@@ -0,0 +1,94 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimeoutsSkipRemainingLevels(t *testing.T) {
+	// timeout_test_slow 一直等到上下文结束，配合取消的算子
+	registerTestOperator("timeout_test_slow", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		<-ctx.Done()
+		return ctx, &OperatorResult{Error: ctx.Err()}
+	})
+	// timeout_test_stuck 不检查上下文，超时后被放弃
+	registerTestOperator("timeout_test_stuck", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		time.Sleep(1500 * time.Millisecond)
+		return ctx, &OperatorResult{Data: "late"}
+	})
+	registerTestOperator("timeout_test_fast", dataOperator("fast"))
+	var downstreamRuns atomic.Int32
+	registerTestOperator("timeout_test_downstream", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		downstreamRuns.Add(1)
+		return ctx, &OperatorResult{Data: "downstream"}
+	})
+
+	tests := []struct {
+		name       string
+		route      string
+		cancel     bool // 请求开始后由调用方取消
+		wantStatus int
+	}{
+		{
+			name:       "node timeout in sequential mode",
+			route:      `{"server_name": "/t", "nodes": [{"name": "timeout_test_slow", "timeout": "20ms", "downstream": "timeout_test_downstream"}, {"name": "timeout_test_downstream"}]}`,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:       "operator ignoring cancellation is abandoned",
+			route:      `{"server_name": "/t", "nodes": [{"name": "timeout_test_stuck", "timeout": "20ms", "downstream": "timeout_test_downstream"}, {"name": "timeout_test_downstream"}]}`,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:       "route timeout in sequential mode",
+			route:      `{"server_name": "/t", "timeout": "20ms", "nodes": [{"name": "timeout_test_slow", "downstream": "timeout_test_downstream"}, {"name": "timeout_test_downstream"}]}`,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name: "node timeout in parallel mode",
+			route: `{"server_name": "/t", "mode": "parallel", "nodes": [
+			  {"name": "timeout_test_slow", "timeout": "20ms", "downstream": "timeout_test_downstream"},
+			  {"name": "timeout_test_fast", "downstream": "timeout_test_downstream"},
+			  {"name": "timeout_test_downstream"}]}`,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:       "route timeout in parallel mode",
+			route:      `{"server_name": "/t", "mode": "parallel", "timeout": "20ms", "nodes": [{"name": "timeout_test_slow", "downstream": "timeout_test_downstream"}, {"name": "timeout_test_downstream"}]}`,
+			wantStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:       "client cancellation",
+			route:      `{"server_name": "/t", "nodes": [{"name": "timeout_test_slow", "downstream": "timeout_test_downstream"}, {"name": "timeout_test_downstream"}]}`,
+			cancel:     true,
+			wantStatus: StatusClientClosedRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstreamRuns.Store(0)
+			s := newTestScheduler(t, "["+tt.route+"]")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				time.AfterFunc(20*time.Millisecond, cancel)
+			}
+
+			start := time.Now()
+			_, err := s.Run(ctx, "/t", ExecuteOptions{})
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("route took %v, want it to stop at the deadline", elapsed)
+			}
+			if got := statusOf(err); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%v)", got, tt.wantStatus, err)
+			}
+			if runs := downstreamRuns.Load(); runs != 0 {
+				t.Errorf("downstream ran %d times, want the remaining levels skipped", runs)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package framework
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span 一段被计时的操作，字段与 OpenTelemetry 的 span 模型对应：
+// 同一请求内的 span 共享 TraceID，通过 ParentSpanID 组成树
+type Span struct {
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId,omitempty"`
+	Name         string                 `json:"name"`
+	StartTime    time.Time              `json:"startTime"`
+	EndTime      time.Time              `json:"endTime"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Status       string                 `json:"status"` // ok / error
+	StatusMsg    string                 `json:"statusMessage,omitempty"`
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// SpanExporter 接收已结束的 span，实现需要并发安全
+type SpanExporter interface {
+	ExportSpan(span *Span)
+}
+
+type exporterHolder struct {
+	exporter SpanExporter
+}
+
+var spanExporter atomic.Pointer[exporterHolder]
+
+// SetSpanExporter 设置全局 span 导出器，传入 nil 时关闭导出（执行时间线仍会记录）
+func SetSpanExporter(exporter SpanExporter) {
+	spanExporter.Store(&exporterHolder{exporter: exporter})
+}
+
+type spanKey struct{}
+
+// SpanFromContext 获取上下文中当前的 span，不存在时返回 nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan 在上下文当前 span 下创建子 span，算子可用于记录内部耗时，结束时调用 End
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	var span *Span
+	if parent != nil {
+		span = parent.child(name)
+	} else {
+		span = newSpan(newTraceID(), "", name)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func newSpan(traceID, parentSpanID, name string) *Span {
+	return &Span{
+		TraceID:      traceID,
+		SpanID:       newSpanID(),
+		ParentSpanID: parentSpanID,
+		Name:         name,
+		StartTime:    time.Now(),
+		Attributes:   make(map[string]interface{}),
+	}
+}
+
+func (s *Span) child(name string) *Span {
+	return newSpan(s.TraceID, s.SpanID, name)
+}
+
+// SetAttribute 设置 span 属性
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// End 结束 span 并导出，err 非空时标记为失败；重复调用无效
+func (s *Span) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.Status = "ok"
+	if err != nil {
+		s.Status = "error"
+		s.StatusMsg = err.Error()
+		s.Attributes["error"] = true
+	}
+	s.mu.Unlock()
+
+	if holder := spanExporter.Load(); holder != nil && holder.exporter != nil {
+		holder.exporter.ExportSpan(s)
+	}
+}
+
+// Duration span 的耗时，未结束时为 0
+func (s *Span) Duration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		return 0
+	}
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// StdoutExporter 每个 span 输出一行 JSON，用于本地调试
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutExporter 创建输出到 w 的导出器
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+func (e *StdoutExporter) ExportSpan(span *Span) {
+	span.mu.Lock()
+	data, err := json.Marshal(span)
+	span.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(append(data, '\n'))
+}
+
+// InMemoryExporter 将 span 保存在内存中，用于测试
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans 返回已导出的 span 副本
+func (e *InMemoryExporter) Spans() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Span(nil), e.spans...)
+}
+
+// Reset 清空已导出的 span
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// startRequestSpan 为一次路由执行创建根 span，请求元数据携带 W3C traceparent 时沿用其 trace
+func startRequestSpan(r *Request, name string) *Span {
+	if traceID, parentID, ok := parseTraceparent(r.Metadata.Get("traceparent")); ok {
+		return newSpan(traceID, parentID, name)
+	}
+	return newSpan(newTraceID(), "", name)
+}
+
+// parseTraceparent 解析 "00-<trace-id>-<parent-id>-<flags>"
+func parseTraceparent(header string) (string, string, bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+	if !isHex(parts[1]) || !isHex(parts[2]) || strings.Trim(parts[1], "0") == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func newTraceID() string {
+	return randomHex(16)
+}
+
+func newSpanID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
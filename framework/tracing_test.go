@@ -0,0 +1,189 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// useSpanExporter 在测试期间安装内存导出器，结束时关闭导出
+func useSpanExporter(t *testing.T) *InMemoryExporter {
+	t.Helper()
+	exporter := NewInMemoryExporter()
+	SetSpanExporter(exporter)
+	t.Cleanup(func() { SetSpanExporter(nil) })
+	return exporter
+}
+
+// spansByName 返回属于 traceID 的 span，按名称索引
+func spansByName(exporter *InMemoryExporter, traceID string) map[string]*Span {
+	spans := make(map[string]*Span)
+	for _, span := range exporter.Spans() {
+		if span.TraceID == traceID {
+			spans[span.Name] = span
+		}
+	}
+	return spans
+}
+
+func TestOperatorSpans(t *testing.T) {
+	exporter := useSpanExporter(t)
+
+	registerTestOperator("tracing_test_slow", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		// 算子内部创建的 span 挂在节点 span 下
+		_, span := StartSpan(ctx, "inner")
+		time.Sleep(10 * time.Millisecond)
+		span.End(nil)
+		return ctx, &OperatorResult{Data: "slow"}
+	})
+	registerTestOperator("tracing_test_ok", dataOperator("ok"))
+	registerTestOperator("tracing_test_fail", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("bad input", "invalid", http.StatusBadRequest)}
+	})
+
+	s := newTestScheduler(t, `[{"server_name": "/trace", "nodes": [
+		{"name": "a", "operator": "tracing_test_slow", "downstream": "b"},
+		{"name": "b", "operator": "tracing_test_ok", "downstream": "c"},
+		{"name": "c", "operator": "tracing_test_fail"}
+	]}]`)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	request := NewRequest(http.MethodGet, "/trace", nil)
+	request.Metadata.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	output, err := s.Run(context.Background(), "/trace", ExecuteOptions{Request: request})
+	if err == nil {
+		t.Fatal("Run succeeded, want the error of node c")
+	}
+	if output.TraceID != traceID {
+		t.Fatalf("TraceID = %q, want the traceparent trace %q", output.TraceID, traceID)
+	}
+
+	spans := spansByName(exporter, traceID)
+	root := spans["route /trace"]
+	if root == nil {
+		t.Fatalf("no route span, got %d spans", len(spans))
+	}
+	if root.ParentSpanID != parentID {
+		t.Errorf("route span parent = %q, want %q", root.ParentSpanID, parentID)
+	}
+	if root.Status != "error" || root.Attributes["route"] != "/trace" {
+		t.Errorf("route span = %s %v", root.Status, root.Attributes)
+	}
+
+	tests := []struct {
+		node       string
+		operator   string
+		level      int
+		wantStatus string
+		wantCode   interface{}
+	}{
+		{node: "a", operator: "tracing_test_slow", level: 0, wantStatus: "ok"},
+		{node: "b", operator: "tracing_test_ok", level: 1, wantStatus: "ok"},
+		{node: "c", operator: "tracing_test_fail", level: 2, wantStatus: "error", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		span := spans["operator "+tt.node]
+		if span == nil {
+			t.Errorf("no span for node %s", tt.node)
+			continue
+		}
+		if span.ParentSpanID != root.SpanID {
+			t.Errorf("node %s parent = %q, want route span %q", tt.node, span.ParentSpanID, root.SpanID)
+		}
+		if span.Status != tt.wantStatus {
+			t.Errorf("node %s status = %s, want %s", tt.node, span.Status, tt.wantStatus)
+		}
+		attrs := span.Attributes
+		if attrs["route"] != "/trace" || attrs["node"] != tt.node || attrs["operator"] != tt.operator || attrs["level"] != tt.level {
+			t.Errorf("node %s attributes = %v", tt.node, attrs)
+		}
+		if attrs["attempts"] != 1 || attrs["http.status_code"] != tt.wantCode {
+			t.Errorf("node %s attempts/status code = %v/%v", tt.node, attrs["attempts"], attrs["http.status_code"])
+		}
+		if _, failed := attrs["error"]; failed != (tt.wantStatus == "error") {
+			t.Errorf("node %s error attribute = %v", tt.node, attrs["error"])
+		}
+	}
+
+	if inner, node := spans["inner"], spans["operator a"]; inner == nil || node == nil || inner.ParentSpanID != node.SpanID {
+		t.Errorf("operator span is not a child of its node span")
+	}
+
+	for _, result := range output.Results {
+		if result.OperatorName == "a" && result.Duration < 10 {
+			t.Errorf("node a Duration = %dms, want at least its 10ms sleep", result.Duration)
+		}
+	}
+}
+
+func TestExecutionTimeline(t *testing.T) {
+	registerTestOperator("timeline_test_slow", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		time.Sleep(10 * time.Millisecond)
+		return ctx, &OperatorResult{Data: "slow"}
+	})
+	registerTestOperator("timeline_test_ok", dataOperator("ok"))
+	registerTestOperator("timeline_test_fail", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("bad input", "invalid", http.StatusBadRequest)}
+	})
+
+	const nodes = `"nodes": [
+		{"name": "root", "operator": "timeline_test_slow", "downstream": [
+			{"node": "left"}, {"node": "right"}, {"node": "skipped", "when": "false"}
+		]},
+		{"name": "left", "operator": "timeline_test_ok"},
+		{"name": "right", "operator": "timeline_test_fail"},
+		{"name": "skipped", "operator": "timeline_test_ok"}
+	]`
+
+	for _, mode := range []string{ModeSequential, ModeParallel} {
+		t.Run(mode, func(t *testing.T) {
+			// 未设置导出器时时间线同样记录
+			SetSpanExporter(nil)
+			s := newTestScheduler(t, `[{"server_name": "/timeline", "mode": "`+mode+`", `+nodes+`}]`)
+
+			output, err := s.Run(context.Background(), "/timeline", ExecuteOptions{})
+			if err == nil {
+				t.Fatal("Run succeeded, want the error of node right")
+			}
+			if len(output.Timeline) != len(output.Results) {
+				t.Fatalf("timeline has %d entries for %d results", len(output.Timeline), len(output.Results))
+			}
+
+			entries := make(map[string]TimelineEntry)
+			for i, entry := range output.Timeline {
+				if entry.Node != output.Results[i].OperatorName {
+					t.Errorf("timeline[%d] = %s, want result order %s", i, entry.Node, output.Results[i].OperatorName)
+				}
+				entries[entry.Node] = entry
+			}
+
+			root := entries["root"]
+			if root.Operator != "timeline_test_slow" || root.Level != 0 || root.Attempts != 1 {
+				t.Errorf("root entry = %+v", root)
+			}
+			if root.Duration < 10 {
+				t.Errorf("root duration = %.3fms, want at least its 10ms sleep", root.Duration)
+			}
+			for _, node := range []string{"left", "right"} {
+				entry := entries[node]
+				if entry.Level != 1 {
+					t.Errorf("%s level = %d, want 1", node, entry.Level)
+				}
+				// 下游在上游结束后才开始，时间以微秒截断，比较时留出浮点误差
+				if entry.StartMs < root.StartMs+root.Duration-0.001 {
+					t.Errorf("%s started at %.3fms, before root ended at %.3fms", node, entry.StartMs, root.StartMs+root.Duration)
+				}
+			}
+			if right := entries["right"]; right.Error == "" {
+				t.Errorf("right entry has no error: %+v", right)
+			}
+			if skipped, ok := entries["skipped"]; ok && !skipped.Skipped {
+				t.Errorf("skipped entry = %+v", skipped)
+			}
+		})
+	}
+}
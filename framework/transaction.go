@@ -0,0 +1,133 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// Transaction 路由级事务，由 TransactionBeginner 创建
+type Transaction interface {
+	Commit() error
+	Rollback() error
+}
+
+// TransactionBeginner 为一次路由执行开启事务
+type TransactionBeginner func(ctx context.Context) (Transaction, error)
+
+// TxKey 请求状态中当前路由的事务，只在 transactional 路由中存在
+var TxKey = NewKey[Transaction]("transaction")
+
+// commitHooksKey 请求状态中事务提交后执行的操作，只在 transactional 路由中存在
+var commitHooksKey = NewKey[*commitHooks]("transaction.after_commit")
+
+// commitHooks 由 AfterCommit 登记的操作
+type commitHooks struct {
+	mu    sync.Mutex
+	hooks []func(ctx context.Context)
+}
+
+// AfterCommit 登记路由事务提交后执行的操作，如清除缓存：提交前清除时，
+// 并发请求可能在提交前读到旧数据并重新写入缓存。
+// 不在 transactional 路由中时写入已生效，hook 立即执行；事务回滚时 hook 被丢弃
+func AfterCommit(ctx context.Context, hook func(ctx context.Context)) {
+	hooks, ok := Get(ctx, commitHooksKey)
+	if !ok {
+		hook(ctx)
+		return
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.hooks = append(hooks.hooks, hook)
+}
+
+// run 按登记顺序执行，hook 不随请求取消，panic 只记录日志
+func (h *commitHooks) run(ctx context.Context) {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					log.Printf("After-commit hook panicked: %v\n%s", recovered, debug.Stack())
+				}
+			}()
+			hook(ctx)
+		}()
+	}
+}
+
+var (
+	transactionBeginner TransactionBeginner
+	transactionMutex    sync.RWMutex
+)
+
+// SetTransactionBeginner 设置开启路由级事务的方法，transactional 路由加载前必须设置
+func SetTransactionBeginner(beginner TransactionBeginner) {
+	transactionMutex.Lock()
+	defer transactionMutex.Unlock()
+	transactionBeginner = beginner
+}
+
+func getTransactionBeginner() TransactionBeginner {
+	transactionMutex.RLock()
+	defer transactionMutex.RUnlock()
+	return transactionBeginner
+}
+
+// validateTransactional 事务只能在顺序模式下使用：并行节点共享同一事务连接会相互干扰
+func (c *RouteConfig) validateTransactional() error {
+	if !c.Transactional {
+		return nil
+	}
+	if c.Mode == ModeParallel {
+		return errors.New("transactional routes must use sequential mode")
+	}
+	if getTransactionBeginner() == nil {
+		return errors.New("transactional route requires a transaction beginner")
+	}
+	return nil
+}
+
+// executeTransactional 在同一事务中顺序执行路由：任一节点失败时回滚，全部成功时提交
+func (s *Scheduler) executeTransactional(ctx context.Context, exec *execution) ([]ExecutionResult, error) {
+	beginner := getTransactionBeginner()
+	if beginner == nil {
+		return nil, types.NewApiError("事务不可用", "No transaction beginner configured", http.StatusInternalServerError)
+	}
+
+	tx, err := beginner(ctx)
+	if err != nil {
+		return nil, types.NewApiError("事务开启失败", err.Error(), http.StatusServiceUnavailable)
+	}
+	hooks := &commitHooks{}
+	if err := Set(ctx, TxKey, tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	Set(ctx, commitHooksKey, hooks)
+
+	results, err := s.executeSequential(ctx, exec)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Printf("Transaction rollback for %s failed: %v", exec.plan.serverName, rollbackErr)
+		}
+		return results, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Transaction commit for %s failed: %v", exec.plan.serverName, err)
+		return results, types.NewApiError("事务提交失败", err.Error(), http.StatusInternalServerError)
+	}
+	hooks.run(ctx)
+	return results, nil
+}
@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// recordingTransaction 按顺序记录事务事件
+type recordingTransaction struct {
+	mu     *sync.Mutex
+	events *[]string
+}
+
+func (t recordingTransaction) Commit() error   { t.record("commit"); return nil }
+func (t recordingTransaction) Rollback() error { t.record("rollback"); return nil }
+
+func (t recordingTransaction) record(event string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.events = append(*t.events, event)
+}
+
+func TestTransactionalTimeoutWaitsForOperator(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	tx := recordingTransaction{mu: &mu, events: &events}
+	SetTransactionBeginner(func(ctx context.Context) (Transaction, error) { return tx, nil })
+	defer SetTransactionBeginner(nil)
+
+	// 算子不配合取消，超时后仍在使用事务
+	registerTestOperator("tx_test_slow", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		time.Sleep(100 * time.Millisecond)
+		tx.record("operator returned")
+		return ctx, &OperatorResult{}
+	})
+
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "node timeout",
+			config: `[{"server_name": "/tx", "transactional": true, "nodes": [{"name": "tx_test_slow", "timeout": "20ms"}]}]`,
+		},
+		{
+			name:   "route timeout",
+			config: `[{"server_name": "/tx", "transactional": true, "timeout": "20ms", "nodes": [{"name": "tx_test_slow"}]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			s := newTestScheduler(t, tt.config)
+
+			_, err := s.Run(context.Background(), "/tx", ExecuteOptions{})
+			var apiErr *types.ApiError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusGatewayTimeout {
+				t.Fatalf("err = %v, want 504", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := strings.Join(events, ","); got != "operator returned,rollback" {
+				t.Errorf("events = %q, want rollback after the operator returned", got)
+			}
+		})
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	tx := recordingTransaction{mu: &mu, events: &events}
+	SetTransactionBeginner(func(ctx context.Context) (Transaction, error) { return tx, nil })
+	defer SetTransactionBeginner(nil)
+
+	registerTestOperator("tx_test_write", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		AfterCommit(ctx, func(ctx context.Context) { tx.record("purge") })
+		return ctx, &OperatorResult{}
+	})
+	registerTestOperator("tx_test_fail", func(ctx context.Context, req *Request) (context.Context, *OperatorResult) {
+		return ctx, &OperatorResult{Error: types.NewApiError("失败", "failed", http.StatusConflict)}
+	})
+
+	tests := []struct {
+		name  string
+		route string
+		want  string
+	}{
+		{
+			name:  "runs after commit",
+			route: `{"server_name": "/tx", "transactional": true, "nodes": [{"name": "tx_test_write"}]}`,
+			want:  "commit,purge",
+		},
+		{
+			name:  "dropped on rollback",
+			route: `{"server_name": "/tx", "transactional": true, "dependencies": {"tx_test_write": "tx_test_fail"}}`,
+			want:  "rollback",
+		},
+		{
+			name:  "immediate without transaction",
+			route: `{"server_name": "/tx", "dependencies": {"tx_test_write": "tx_test_fail"}}`,
+			want:  "purge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			s := newTestScheduler(t, "["+tt.route+"]")
+			s.Run(context.Background(), "/tx", ExecuteOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := strings.Join(events, ","); got != tt.want {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package framework
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+const (
+	fieldInBody  = "body"
+	fieldInQuery = "query"
+)
+
+// Compile 检查 schema 并预编译 pattern，校验前必须调用。不支持 $ref
+func (s *Schema) Compile() error {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		return fmt.Errorf("$ref %s is not supported", s.Ref)
+	}
+	switch s.Type {
+	case "", "object", "array", "string", "integer", "number", "boolean", "null":
+	default:
+		return fmt.Errorf("unknown type %s", s.Type)
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	for name, property := range s.Properties {
+		if err := property.Compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if err := s.AdditionalProperties.Compile(); err != nil {
+		return fmt.Errorf("additionalProperties: %w", err)
+	}
+	if err := s.Items.Compile(); err != nil {
+		return fmt.Errorf("items: %w", err)
+	}
+	return nil
+}
+
+// ValidateJSON 校验 JSON 请求体，返回全部不合法的字段
+func (s *Schema) ValidateJSON(data []byte) []types.FieldError {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []types.FieldError{{In: fieldInBody, Message: "invalid JSON: " + err.Error()}}
+	}
+	v := validation{in: fieldInBody}
+	v.validate(s, value, "")
+	return v.errors
+}
+
+// ValidateQuery 校验查询参数。schema 的每个属性对应一个参数，按属性类型转换后校验；
+// array 类型的属性取参数的全部值，其余取第一个值。未声明的参数不校验
+func (s *Schema) ValidateQuery(query url.Values) []types.FieldError {
+	v := validation{in: fieldInQuery}
+	for _, name := range s.Required {
+		if _, ok := query[name]; !ok {
+			v.fail(name, "is required")
+		}
+	}
+	for _, name := range sortedSchemaKeys(s.Properties) {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+		property := s.Properties[name]
+		if property.Type == "array" {
+			items := make([]interface{}, len(values))
+			for i, raw := range values {
+				items[i] = queryValue(property.Items, raw)
+			}
+			v.validate(property, items, name)
+			continue
+		}
+		v.validate(property, queryValue(property, values[0]), name)
+	}
+	return v.errors
+}
+
+// queryValue 按 schema 类型转换查询参数，无法转换时保留字符串，由类型校验报错
+func queryValue(s *Schema, raw string) interface{} {
+	if s == nil {
+		return raw
+	}
+	switch s.Type {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// validation 收集一次校验中的全部字段错误
+type validation struct {
+	in     string
+	errors []types.FieldError
+}
+
+func (v *validation) fail(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, types.FieldError{In: v.in, Field: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate 校验 encoding/json 解码得到的值，类型不符时不再检查其余约束
+func (v *validation) validate(s *Schema, value interface{}, path string) {
+	if s == nil {
+		return
+	}
+	if s.Type != "" && !matchesType(s.Type, value) {
+		v.fail(path, "must be %s", typeDescription(s.Type))
+		return
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, value) {
+		v.fail(path, "must be one of %s", enumDescription(s.Enum))
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.validateString(s, value, path)
+	case float64:
+		v.validateNumber(s, value, path)
+	case []interface{}:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			v.fail(path, "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			v.fail(path, "must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range value {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				v.fail(joinPath(path, name), "is required")
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, declared := s.Properties[name]
+			if !declared {
+				property = s.AdditionalProperties
+			}
+			v.validate(property, value[name], joinPath(path, name))
+		}
+	}
+}
+
+func (v *validation) validateString(s *Schema, value, path string) {
+	length := utf8.RuneCountInString(value)
+	if s.MinLength != nil && length < *s.MinLength {
+		v.fail(path, "length must be at least %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(path, "length must be at most %d", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		pattern := s.pattern
+		if pattern == nil {
+			pattern = regexp.MustCompile(s.Pattern)
+		}
+		if !pattern.MatchString(value) {
+			v.fail(path, "must match pattern %s", s.Pattern)
+		}
+	}
+	if err := checkFormat(s.Format, value); err != nil {
+		v.fail(path, "%v", err)
+	}
+}
+
+func (v *validation) validateNumber(s *Schema, value float64, path string) {
+	if s.Minimum != nil && value < *s.Minimum {
+		v.fail(path, "must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && value > *s.Maximum {
+		v.fail(path, "must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && value <= *s.ExclusiveMinimum {
+		v.fail(path, "must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && value >= *s.ExclusiveMaximum {
+		v.fail(path, "must be < %v", *s.ExclusiveMaximum)
+	}
+}
+
+// checkFormat 校验常用格式，未知格式只作为说明不校验
+func checkFormat(format, value string) error {
+	switch format {
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return errors.New("must be a date in YYYY-MM-DD format")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return errors.New("must be an RFC 3339 date-time")
+		}
+	case "email":
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			return errors.New("must be a valid email address")
+		}
+	case "byte":
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return errors.New("must be base64 encoded")
+		}
+	}
+	return nil
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return schemaType == "null"
+	case bool:
+		return schemaType == "boolean"
+	case float64:
+		return schemaType == "number" || (schemaType == "integer" && value == math.Trunc(value))
+	case string:
+		return schemaType == "string"
+	case []interface{}:
+		return schemaType == "array"
+	case map[string]interface{}:
+		return schemaType == "object"
+	}
+	return false
+}
+
+func typeDescription(schemaType string) string {
+	switch schemaType {
+	case "object", "array", "integer":
+		return "an " + schemaType
+	case "null":
+		return "null"
+	default:
+		return "a " + schemaType
+	}
+}
+
+// enumContains 枚举值来自 JSON 配置或 schema 标签，数值均为 float64
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, option := range enum {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+func enumDescription(enum []interface{}) string {
+	data, _ := json.Marshal(enum)
+	return string(data)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedSchemaKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,239 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// compileSchema 解析并编译 JSON 形式的 schema
+func compileSchema(t *testing.T, source string) *Schema {
+	t.Helper()
+	var schema Schema
+	if err := json.Unmarshal([]byte(source), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	return &schema
+}
+
+// fieldErrors 将字段错误格式化为 "in:field: message"，便于比较
+func fieldErrors(errs []types.FieldError) []string {
+	formatted := make([]string, len(errs))
+	for i, err := range errs {
+		formatted[i] = err.In + ":" + err.Field + ": " + err.Message
+	}
+	return formatted
+}
+
+func assertFieldErrors(t *testing.T, got []types.FieldError, want []string) {
+	t.Helper()
+	if g, w := strings.Join(fieldErrors(got), "\n"), strings.Join(want, "\n"); g != w {
+		t.Errorf("field errors:\n%s\nwant:\n%s", g, w)
+	}
+}
+
+const recordSchema = `{
+	"type": "object",
+	"required": ["amount", "user"],
+	"properties": {
+		"amount": {"type": "integer", "exclusiveMinimum": 0, "maximum": 5000},
+		"recordTime": {"type": "string", "format": "date-time"},
+		"action": {"type": "string", "enum": ["drank", "skipped"]},
+		"note": {"type": "string", "maxLength": 4},
+		"user": {
+			"type": "object",
+			"required": ["email"],
+			"properties": {
+				"email": {"type": "string", "format": "email"},
+				"code": {"type": "string", "pattern": "^[A-Z]{3}$"}
+			},
+			"additionalProperties": {"type": "string"}
+		},
+		"tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string", "minLength": 1}}
+	}
+}`
+
+func TestValidateJSON(t *testing.T) {
+	schema := compileSchema(t, recordSchema)
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "valid",
+			body: `{"amount": 250, "recordTime": "2024-05-01T08:00:00+08:00", "action": "drank", "note": "茶水咖啡", "user": {"email": "a@example.com", "code": "ABC", "extra": "x"}, "tags": ["a"]}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"amount": }`,
+			want: []string{"body:: invalid JSON: invalid character '}' looking for beginning of value"},
+		},
+		{
+			name: "top-level type",
+			body: `[1]`,
+			want: []string{"body:: must be an object"},
+		},
+		{
+			name: "missing required fields",
+			body: `{}`,
+			want: []string{"body:amount: is required", "body:user: is required"},
+		},
+		{
+			name: "nested required and formats",
+			body: `{"amount": 1, "user": {"code": "abc", "extra": 1}}`,
+			want: []string{
+				"body:user.email: is required",
+				"body:user.code: must match pattern ^[A-Z]{3}$",
+				"body:user.extra: must be a string",
+			},
+		},
+		{
+			name: "numeric bounds and integer type",
+			body: `{"amount": 0, "user": {"email": "a@example.com"}}`,
+			want: []string{"body:amount: must be > 0"},
+		},
+		{
+			name: "fractional integer stops further checks",
+			body: `{"amount": 9000.5, "user": {"email": "a@example.com"}}`,
+			want: []string{"body:amount: must be an integer"},
+		},
+		{
+			name: "enum, format and length",
+			body: `{"amount": 6000, "action": "ate", "recordTime": "2024-05-01", "note": "12345", "user": {"email": "Alice <a@example.com>"}}`,
+			want: []string{
+				`body:action: must be one of ["drank","skipped"]`,
+				"body:amount: must be <= 5000",
+				"body:note: length must be at most 4",
+				"body:recordTime: must be an RFC 3339 date-time",
+				"body:user.email: must be a valid email address",
+			},
+		},
+		{
+			name: "array items use index paths",
+			body: `{"amount": 1, "user": {"email": "a@example.com"}, "tags": ["", 2, "c"]}`,
+			want: []string{
+				"body:tags: must have at most 2 items",
+				"body:tags[0]: length must be at least 1",
+				"body:tags[1]: must be a string",
+			},
+		},
+		{
+			name: "empty array",
+			body: `{"amount": 1, "user": {"email": "a@example.com"}, "tags": []}`,
+			want: []string{"body:tags: must have at least 1 items"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFieldErrors(t, schema.ValidateJSON([]byte(tt.body)), tt.want)
+		})
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	schema := compileSchema(t, `{
+		"type": "object",
+		"required": ["date"],
+		"properties": {
+			"date": {"type": "string", "format": "date"},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+			"ratio": {"type": "number", "exclusiveMaximum": 1},
+			"desc": {"type": "boolean"},
+			"period": {"type": "string", "enum": ["day", "week"]},
+			"ids": {"type": "array", "maxItems": 2, "items": {"type": "integer"}}
+		}
+	}`)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "valid", query: "date=2024-05-01&limit=10&ratio=0.5&desc=true&period=week&ids=1&ids=2&unknown=x"},
+		{name: "missing required", query: "limit=10", want: []string{"query:date: is required"}},
+		{name: "bad date", query: "date=2024-13-01", want: []string{"query:date: must be a date in YYYY-MM-DD format"}},
+		{
+			name:  "type conversion failures",
+			query: "date=2024-05-01&limit=ten&ratio=x&desc=maybe",
+			want: []string{
+				"query:desc: must be a boolean",
+				"query:limit: must be an integer",
+				"query:ratio: must be a number",
+			},
+		},
+		{
+			name:  "bounds and enum",
+			query: "date=2024-05-01&limit=0&ratio=1&period=month",
+			want: []string{
+				"query:limit: must be >= 1",
+				`query:period: must be one of ["day","week"]`,
+				"query:ratio: must be < 1",
+			},
+		},
+		{name: "only first value of scalar", query: "date=2024-05-01&limit=5&limit=500"},
+		{
+			name:  "array values",
+			query: "date=2024-05-01&ids=1&ids=x&ids=3",
+			want: []string{
+				"query:ids: must have at most 2 items",
+				"query:ids[1]: must be an integer",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFieldErrors(t, schema.ValidateQuery(query), tt.want)
+		})
+	}
+}
+
+func TestValidateTaggedStruct(t *testing.T) {
+	type login struct {
+		Email    string `json:"email" schema:"required,format=email"`
+		Password string `json:"password" schema:"required,minLength=6"`
+	}
+	schema := SchemaOf(login{})
+	if err := schema.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	assertFieldErrors(t, schema.ValidateJSON([]byte(`{"email": "nope", "password": "123"}`)), []string{
+		"body:email: must be a valid email address",
+		"body:password: length must be at least 6",
+	})
+}
+
+func TestSchemaCompileErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{source: `{"$ref": "#/components/schemas/User"}`, wantErr: "$ref #/components/schemas/User is not supported"},
+		{source: `{"type": "map"}`, wantErr: "unknown type map"},
+		{source: `{"type": "object", "properties": {"code": {"type": "string", "pattern": "[a-"}}}`, wantErr: `code: invalid pattern "[a-"`},
+		{source: `{"type": "array", "items": {"type": "int"}}`, wantErr: "items: unknown type int"},
+		{source: `{"type": "object", "additionalProperties": {"$ref": "x"}}`, wantErr: "additionalProperties: $ref x is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantErr, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.source), &schema); err != nil {
+				t.Fatal(err)
+			}
+			if err := schema.Compile(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,16 +3,31 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/zhanghuachuan/water-reminder/database"
 	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/metrics"
 	"github.com/zhanghuachuan/water-reminder/operators"
+	"github.com/zhanghuachuan/water-reminder/rpc"
 	"github.com/zhanghuachuan/water-reminder/types"
 )
 
+const (
+	schedulerConfigPath = "config/scheduler_config.json"
+	rpcConfigPath       = "trpc.yaml"
+)
+
 // SchedulerHandler 适配器，使Scheduler兼容http.Handler
 type SchedulerHandler struct {
 	scheduler *framework.Scheduler
@@ -26,16 +41,16 @@ type ResponseHandler struct {
 }
 
 // handleResponse 统一处理HTTP响应
-func handleResponse(w http.ResponseWriter, results []framework.ExecutionResult) {
+func handleResponse(w http.ResponseWriter, output *framework.ExecutionOutput) {
 	w.Header().Set("Content-Type", "application/json")
 
 	// 检查是否有错误
-	for _, result := range results {
+	for _, result := range output.Results {
 		if result.Error != nil {
 			// 检查是否是ApiError类型
 			if apiErr, ok := result.Error.(*types.ApiError); ok {
 				w.WriteHeader(apiErr.StatusCode)
-				json.NewEncoder(w).Encode(types.NewErrorResponse(apiErr.Message, apiErr.ErrorMsg))
+				json.NewEncoder(w).Encode(types.NewApiErrorResponse(apiErr))
 			} else {
 				w.WriteHeader(http.StatusInternalServerError)
 				json.NewEncoder(w).Encode(types.NewErrorResponse("执行失败", result.Error.Error()))
@@ -44,58 +59,135 @@ func handleResponse(w http.ResponseWriter, results []framework.ExecutionResult)
 		}
 	}
 
-	// 获取最后一个算子的结果数据
-	if len(results) > 0 {
-		lastResult := results[len(results)-1]
-		// 这里可以根据需要从上下文中提取数据或使用其他方式获取最终结果
-		if lastResult.Error != nil {
-			// 检查是否是ApiError类型
-			if apiErr, ok := lastResult.Error.(*types.ApiError); ok {
-				w.WriteHeader(apiErr.StatusCode)
-				json.NewEncoder(w).Encode(types.NewErrorResponse(apiErr.Message, apiErr.ErrorMsg))
-			} else {
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(types.NewErrorResponse("执行失败", lastResult.Error.Error()))
-			}
-		} else {
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(types.NewSuccessResponse("操作成功", lastResult.Data))
-		}
+	// 响应数据由路由的 response 配置决定，未配置时为最后一个实际执行的算子的结果
+	resp := output.Response
+	if resp == nil {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(types.NewSuccessResponse("操作成功", output.Body))
 		return
 	}
 
-	// 默认成功响应
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(types.NewSuccessResponse("操作成功", nil))
+	// 算子设置的状态码、响应头与Cookie
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	for _, cookie := range resp.Cookies {
+		http.SetCookie(w, cookie)
+	}
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+
+	switch {
+	case resp.Raw && resp.Body != nil:
+		w.Write(resp.Body)
+	case resp.Raw:
+		json.NewEncoder(w).Encode(output.Body)
+	default:
+		json.NewEncoder(w).Encode(types.NewSuccessResponse("操作成功", output.Body))
+	}
 }
 
-func (h *SchedulerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+// writeTimeline 请求携带 X-Debug-Timeline 头时，通过 Server-Timing 返回各算子耗时
+func writeTimeline(w http.ResponseWriter, r *http.Request, output *framework.ExecutionOutput) {
+	if output == nil || r.Header.Get("X-Debug-Timeline") == "" {
+		return
+	}
 
-	// 从URL路径中获取 server_name
-	// 路径格式例如: /api/login, /api/register 等
-	serverName := r.URL.Path // 默认值
+	entries := make([]string, 0, len(output.Timeline))
+	for _, entry := range output.Timeline {
+		desc := fmt.Sprintf("level %d", entry.Level)
+		switch {
+		case entry.Skipped:
+			desc += ", skipped"
+		case entry.Error != "":
+			desc += ", error"
+		}
+		if entry.Attempts > 1 {
+			desc += fmt.Sprintf(", %d attempts", entry.Attempts)
+		}
+		entries = append(entries, fmt.Sprintf("%s;desc=%q;dur=%.3f", entry.Node, desc, entry.Duration))
+	}
+	w.Header().Set("Server-Timing", strings.Join(entries, ", "))
+	w.Header().Set("X-Trace-Id", output.TraceID)
+}
 
-	// 从路径中提取server_name，例如从 "/login" 中提取
-	if serverName == "" {
-		serverName = "login"
+func hasFailedResult(results []framework.ExecutionResult) bool {
+	for _, result := range results {
+		if result.Error != nil {
+			return true
+		}
 	}
+	return false
+}
 
-	results, err := h.scheduler.Execute(ctx, serverName, framework.ExecuteOptions{
-		Request: r,
-	})
+func (h *SchedulerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// 按HTTP方法和路径模式匹配路由，例如 GET /records/{id}
+	serverName, params, err := h.scheduler.Match(r.Method, r.URL.Path)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(types.NewErrorResponse("调度失败", err.Error()))
+		var methodErr *framework.MethodNotAllowedError
+		switch {
+		case errors.As(err, &methodErr):
+			writeMethodNotAllowed(w, methodErr.Allowed...)
+		default:
+			writeJSON(w, http.StatusNotFound, types.NewErrorResponse("路由不存在", "Not found"))
+		}
+		return
+	}
+
+	// 上下文派生自请求，客户端断开时取消正在执行的算子；路径参数随请求传递给算子
+	req := framework.NewHTTPRequest(r)
+	req.Params = params
+
+	output, err := h.scheduler.Run(r.Context(), serverName, framework.ExecuteOptions{
+		Request: req,
+	})
+	writeTimeline(w, r, output)
+
+	// 算子失败时由 handleResponse 按算子错误返回，其余调度错误（如状态合并冲突）统一返回500
+	if err != nil && (output == nil || !hasFailedResult(output.Results)) {
+		var apiErr *types.ApiError
+		if errors.As(err, &apiErr) {
+			writeJSON(w, apiErr.StatusCode, types.NewApiErrorResponse(apiErr))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, types.NewErrorResponse("调度失败", err.Error()))
 		return
 	}
 
 	// 统一处理HTTP响应
-	handleResponse(w, results)
+	handleResponse(w, output)
+}
+
+// reloadOnSignal 收到SIGHUP时重载调度配置
+func reloadOnSignal(sched *framework.Scheduler) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	for range sigCh {
+		if err := sched.Reload(); err != nil {
+			log.Printf("Config reload rejected, keeping previous plan: %v", err)
+			continue
+		}
+		log.Println("Config reloaded on SIGHUP")
+	}
 }
 
 func main() {
+	// plan 与 openapi 子命令只读取配置，不连接数据库
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "plan":
+			os.Exit(runPlan(os.Args[2:]))
+		case "openapi":
+			os.Exit(runOpenAPI(os.Args[2:]))
+		}
+	}
+
 	// 1. 加载.env配置
 	if err := godotenv.Load(); err != nil {
 		log.Fatal("Error loading .env file:", err)
@@ -106,18 +198,56 @@ func main() {
 		log.Fatal("Failed to initialize databases:", err)
 	}
 
-	// 3. 显式初始化算子
+	// 3. 配置 TRACE_EXPORTER=stdout 时将算子 span 输出到标准输出
+	if os.Getenv("TRACE_EXPORTER") == "stdout" {
+		framework.SetSpanExporter(framework.NewStdoutExporter(os.Stdout))
+	}
+
+	// 4. 显式初始化算子
 	operators.InitializeOperators()
 
-	// 4. 创建调度器并加载配置
+	// 5. 创建调度器并加载配置
 	sched := framework.NewScheduler()
-	if err := sched.LoadConfig("config/scheduler_config.json"); err != nil {
+	if maxWorkers, err := strconv.Atoi(os.Getenv("SCHEDULER_MAX_WORKERS")); err == nil && maxWorkers > 0 {
+		sched.SetMaxWorkers(maxWorkers)
+	}
+	if err := sched.LoadConfig(schedulerConfigPath); err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
-	// 5. 启动HTTP服务
+	// 6. 配置热加载：文件变化或收到SIGHUP时重载，失败时保留旧计划
+	go sched.WatchConfig(context.Background(), 5*time.Second)
+	go reloadOnSignal(sched)
+
+	// 7. 按 trpc.yaml 启动RPC服务，与HTTP服务共用同一个调度器
+	rpcConfig, err := rpc.LoadConfig(rpcConfigPath)
+	if err != nil {
+		log.Fatal("Failed to load RPC config:", err)
+	}
+	rpcServer, err := rpc.NewServer(sched, rpcConfig.Server.Service)
+	if err != nil {
+		log.Fatal("Failed to create RPC server:", err)
+	}
+	go func() {
+		for service, address := range rpcServer.Addresses() {
+			log.Printf("gRPC service %s started on %s", service, address)
+		}
+		if err := rpcServer.ListenAndServe(); err != nil {
+			log.Fatal("RPC server error:", err)
+		}
+	}()
+
+	// 8. 启动HTTP服务
+	mux := http.NewServeMux()
+	mux.Handle("/admin/reload", adminOnly(reloadHandler(sched)))
+	mux.Handle("/admin/panics", adminOnly(panicsHandler(sched)))
+	mux.Handle("/admin/breakers", adminOnly(breakersHandler))
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/openapi.json", openAPIHandler(sched))
+	mux.Handle("/", &SchedulerHandler{scheduler: sched})
+
 	log.Println("Server started on :8080")
-	if err := http.ListenAndServe(":8080", &SchedulerHandler{scheduler: sched}); err != nil {
+	if err := http.ListenAndServe(":8080", mux); err != nil {
 		log.Fatal("Server error:", err)
 	}
 }
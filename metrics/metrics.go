@@ -0,0 +1,339 @@
+// Package metrics 进程内指标注册表，以 Prometheus 文本格式导出
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets 延迟直方图的默认桶（秒）
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry 指标注册表，同名指标只能注册一次
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	describe() (kind string, help string, labels []string)
+	write(b *strings.Builder, name string)
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Default 默认注册表，/metrics 接口导出其中的全部指标
+var Default = NewRegistry()
+
+// register 注册指标；同名且类型、标签一致时返回已注册的指标，便于重复初始化
+func (r *Registry) register(name string, m metric) metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.metrics[name]; ok {
+		kind, _, labels := existing.describe()
+		newKind, _, newLabels := m.describe()
+		if kind != newKind || strings.Join(labels, ",") != strings.Join(newLabels, ",") {
+			panic(fmt.Sprintf("metric %s already registered with a different type or labels", name))
+		}
+		return existing
+	}
+	r.metrics[name] = m
+	return m
+}
+
+// NewCounter 在注册表中创建计数器
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return r.register(name, &Counter{series: newSeries(help, labels)}).(*Counter)
+}
+
+// NewGauge 在注册表中创建仪表
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return r.register(name, &Gauge{series: newSeries(help, labels)}).(*Gauge)
+}
+
+// NewGaugeFunc 创建在导出时调用 fn 取值的仪表
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(name, &gaugeFunc{help: help, fn: fn})
+}
+
+// NewHistogram 在注册表中创建直方图，buckets 为空时使用 DefaultBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return r.register(name, &Histogram{series: newSeries(help, labels), buckets: buckets}).(*Histogram)
+}
+
+// NewCounter 在默认注册表中创建计数器
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewGauge 在默认注册表中创建仪表
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+// NewGaugeFunc 在默认注册表中创建按需取值的仪表
+func NewGaugeFunc(name, help string, fn func() float64) {
+	Default.NewGaugeFunc(name, help, fn)
+}
+
+// NewHistogram 在默认注册表中创建直方图
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// WriteText 以 Prometheus 文本格式（0.0.4）输出全部指标，按名称排序
+func (r *Registry) WriteText(b *strings.Builder) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make(map[string]metric, len(r.metrics))
+	for name, m := range r.metrics {
+		metrics[name] = m
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		m := metrics[name]
+		kind, help, _ := m.describe()
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeHelp(help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, kind)
+		m.write(b, name)
+	}
+}
+
+// Handler 导出注册表的 HTTP 处理器
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var b strings.Builder
+		r.WriteText(&b)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(b.String()))
+	})
+}
+
+// Handler 导出默认注册表
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// series 按标签值区分的一组时间序列
+type series struct {
+	mu     sync.Mutex
+	help   string
+	labels []string
+	values map[string]*seriesValue // 标签值拼接 -> 序列
+}
+
+type seriesValue struct {
+	labelValues []string
+	value       float64
+	buckets     []uint64 // 仅直方图使用，与桶一一对应（非累计）
+	count       uint64
+}
+
+func newSeries(help string, labels []string) series {
+	return series{help: help, labels: labels, values: make(map[string]*seriesValue)}
+}
+
+// get 返回标签值对应的序列，调用方需持有锁
+func (s *series) get(labelValues []string) *seriesValue {
+	if len(labelValues) != len(s.labels) {
+		panic(fmt.Sprintf("expected %d label values, got %d", len(s.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	v, ok := s.values[key]
+	if !ok {
+		v = &seriesValue{labelValues: append([]string(nil), labelValues...)}
+		s.values[key] = v
+	}
+	return v
+}
+
+// sorted 按标签值排序的序列快照，调用方需持有锁
+func (s *series) sorted() []*seriesValue {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]*seriesValue, len(keys))
+	for i, key := range keys {
+		values[i] = s.values[key]
+	}
+	return values
+}
+
+func (s *series) labelString(v *seriesValue, extra ...string) string {
+	pairs := make([]string, 0, len(s.labels)+1)
+	for i, label := range s.labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label, escapeLabel(v.labelValues[i])))
+	}
+	pairs = append(pairs, extra...)
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter 只增不减的计数器
+type Counter struct {
+	series
+}
+
+func (c *Counter) describe() (string, string, []string) {
+	return "counter", c.help, c.labels
+}
+
+// Inc 计数加一
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add 计数增加 delta，delta 不能为负
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("counter cannot decrease")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(labelValues).value += delta
+}
+
+func (c *Counter) write(b *strings.Builder, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range c.sorted() {
+		fmt.Fprintf(b, "%s%s %s\n", name, c.labelString(v), formatFloat(v.value))
+	}
+}
+
+// Gauge 可增可减的仪表
+type Gauge struct {
+	series
+}
+
+func (g *Gauge) describe() (string, string, []string) {
+	return "gauge", g.help, g.labels
+}
+
+// Set 设置当前值
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.get(labelValues).value = value
+}
+
+// Add 当前值增加 delta（可为负）
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.get(labelValues).value += delta
+}
+
+// Inc 当前值加一
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec 当前值减一
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+func (g *Gauge) write(b *strings.Builder, name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.sorted() {
+		fmt.Fprintf(b, "%s%s %s\n", name, g.labelString(v), formatFloat(v.value))
+	}
+}
+
+type gaugeFunc struct {
+	help string
+	fn   func() float64
+}
+
+func (g *gaugeFunc) describe() (string, string, []string) {
+	return "gauge", g.help, nil
+}
+
+func (g *gaugeFunc) write(b *strings.Builder, name string) {
+	fmt.Fprintf(b, "%s %s\n", name, formatFloat(g.fn()))
+}
+
+// Histogram 按桶统计观测值分布
+type Histogram struct {
+	series
+	buckets []float64
+}
+
+func (h *Histogram) describe() (string, string, []string) {
+	return "histogram", h.help, h.labels
+}
+
+// Observe 记录一次观测值
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	v := h.get(labelValues)
+	if v.buckets == nil {
+		v.buckets = make([]uint64, len(h.buckets))
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		v.buckets[i]++
+	}
+	v.count++
+	v.value += value
+}
+
+func (h *Histogram) write(b *strings.Builder, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, v := range h.sorted() {
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += v.buckets[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", name, h.labelString(v, fmt.Sprintf("le=\"%s\"", formatFloat(upper))), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, h.labelString(v, "le=\"+Inf\""), v.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", name, h.labelString(v), formatFloat(v.value))
+		fmt.Fprintf(b, "%s_count%s %d\n", name, h.labelString(v), v.count)
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+)
+
+var apiInfo = framework.OpenAPIInfo{
+	Title:   "Water Reminder API",
+	Version: "1.0.0",
+}
+
+// openAPIHandler 按当前生效的路由返回 OpenAPI 文档，配置热加载后文档随之更新。
+// 文档直接输出，不使用 ApiResponse 包装，便于 Swagger UI 等工具读取
+func openAPIHandler(sched *framework.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeJSON(w, http.StatusOK, sched.OpenAPI(apiInfo))
+	}
+}
+
+// runOpenAPI 实现 openapi 子命令：加载调度配置并将 OpenAPI 文档输出到 stdout，不连接数据库
+func runOpenAPI(args []string) int {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	configPath := flags.String("config", schedulerConfigPath, "scheduler config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	sched := framework.NewScheduler()
+	if err := sched.LoadConfig(*configPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sched.OpenAPI(apiInfo)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
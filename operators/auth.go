@@ -3,7 +3,6 @@ package operators
 import (
 	"context"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/zhanghuachuan/water-reminder/database"
@@ -22,19 +21,37 @@ func (o *AuthOperator) Name() string {
 	return "auth"
 }
 
-func (o *AuthOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
-	// 从Authorization头获取token
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Authorization header required", "Unauthorized", http.StatusUnauthorized),
-		}
+// Schema 认证算子不读取请求体，要求 Bearer 令牌
+func (o *AuthOperator) Schema(method string) *framework.OperatorSchema {
+	return &framework.OperatorSchema{
+		Summary:  "令牌校验",
+		Response: AuthResponse{},
+		Errors:   []int{http.StatusUnauthorized, http.StatusServiceUnavailable},
+		Auth:     true,
+	}
+}
+
+// Idempotent 校验与续期token可安全重复执行
+func (o *AuthOperator) Idempotent() bool {
+	return true
+}
+
+func (o *AuthOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	// 后台任务等可信来源直接指定用户，不经过令牌校验
+	if r.Identity.Subject != "" {
+		return o.authenticated(ctx, r.Identity.Subject)
 	}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
+	// 令牌由请求适配器从 Authorization 元数据中取出
+	tokenString := r.Identity.Token
+	if tokenString == "" {
+		if r.Metadata.Get("Authorization") != "" {
+			return ctx, &framework.OperatorResult{
+				Error: types.NewApiError("Invalid authorization format", "Unauthorized", http.StatusUnauthorized),
+			}
+		}
 		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Invalid authorization format", "Unauthorized", http.StatusUnauthorized),
+			Error: types.NewApiError("Authorization header required", "Unauthorized", http.StatusUnauthorized),
 		}
 	}
 
@@ -46,9 +63,14 @@ func (o *AuthOperator) Execute(ctx context.Context, r *http.Request) (context.Co
 		}
 	}
 
-	// 验证Redis中的token
+	// 验证Redis中的token，Redis不可用属于临时错误，返回503以便按策略重试
 	valid, err := database.IsTokenValid(userID, tokenString)
-	if err != nil || !valid {
+	if err != nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("Token store unavailable", "ServiceUnavailable", http.StatusServiceUnavailable),
+		}
+	}
+	if !valid {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Invalid token", "Unauthorized", http.StatusUnauthorized),
 		}
@@ -58,12 +80,21 @@ func (o *AuthOperator) Execute(ctx context.Context, r *http.Request) (context.Co
 	err = database.RefreshTokenInRedis(userID, 24*time.Hour)
 	if err != nil {
 		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Failed to refresh token", "InternalServerError", http.StatusInternalServerError),
+			Error: types.NewApiError("Failed to refresh token", "ServiceUnavailable", http.StatusServiceUnavailable),
 		}
 	}
 
-	// 将用户信息存入上下文
-	ctx = context.WithValue(ctx, "user", &types.User{ID: userID})
+	activeUsers.seen(userID)
+	return o.authenticated(ctx, userID)
+}
+
+// authenticated 将用户信息存入请求状态
+func (o *AuthOperator) authenticated(ctx context.Context, userID string) (context.Context, *framework.OperatorResult) {
+	if err := framework.Set(ctx, UserKey, &types.User{ID: userID}); err != nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("请求状态不可用", err.Error(), http.StatusInternalServerError),
+		}
+	}
 
 	return ctx, &framework.OperatorResult{
 		Data: AuthResponse{UserID: userID},
@@ -0,0 +1,273 @@
+package operators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zhanghuachuan/water-reminder/database"
+	"github.com/zhanghuachuan/water-reminder/framework"
+)
+
+// 缓存作用范围
+const (
+	cacheScopeUser   = "user"   // 按用户隔离，未认证的请求不使用缓存
+	cacheScopeGlobal = "global" // 所有用户共享
+)
+
+// CacheConfig cache 装饰器配置
+type CacheConfig struct {
+	TTL   framework.Duration `json:"ttl"`
+	Tags  []string           `json:"tags"`  // 失效标签，写操作通过 invalidate 装饰器按标签清除
+	Scope string             `json:"scope"` // user（默认）或 global
+}
+
+// InvalidateConfig invalidate 装饰器配置
+type InvalidateConfig struct {
+	Tags  []string `json:"tags"`
+	Scope string   `json:"scope"` // 与被清除的缓存的 scope 一致，默认 user
+}
+
+// cacheEntry Redis 中保存的算子结果。
+// 命中缓存时 Data 按算子 Schema 声明的 Response 类型解码，下游可通过 Output[T] 读取
+type cacheEntry struct {
+	Data     json.RawMessage     `json:"data"`
+	Pointer  bool                `json:"pointer,omitempty"` // 算子返回的是声明类型的指针
+	Response *framework.Response `json:"response,omitempty"`
+}
+
+// CachedOperator 将内层算子的成功结果缓存在 Redis 中。
+// 只缓存算子通过 SchemaProvider 声明了 Response 类型的请求方法
+type CachedOperator struct {
+	inner  framework.Operator
+	config CacheConfig
+}
+
+// NewCachedOperator 创建 cache 装饰器包装的算子
+func NewCachedOperator(op framework.Operator, config map[string]interface{}) (framework.Operator, error) {
+	var cfg CacheConfig
+	if err := framework.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.TTL.Duration <= 0 {
+		return nil, fmt.Errorf("cache ttl must be positive")
+	}
+	if err := validateCacheScope(cfg.Scope); err != nil {
+		return nil, err
+	}
+	declared := false
+	for _, method := range []string{"", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		if schema := framework.SchemaFor(op, method); schema != nil && schema.Response != nil {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		return nil, fmt.Errorf("cache requires operator %s to declare a response type", op.Name())
+	}
+	return &CachedOperator{inner: op, config: cfg}, nil
+}
+
+func (o *CachedOperator) Name() string {
+	return o.inner.Name()
+}
+
+func (o *CachedOperator) Unwrap() framework.Operator {
+	return o.inner
+}
+
+func (o *CachedOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	owner, ok := cacheOwner(ctx, o.config.Scope)
+	responseType := o.responseType(r.Method)
+	if !ok || responseType == nil || database.GetRedis() == nil {
+		return o.inner.Execute(ctx, r)
+	}
+
+	key := o.cacheKey(ctx, r, owner)
+	rdb := database.GetRedis().Client
+	if cached, err := rdb.Get(ctx, key).Bytes(); err == nil {
+		var entry cacheEntry
+		if json.Unmarshal(cached, &entry) == nil {
+			data := reflect.New(responseType)
+			if json.Unmarshal(entry.Data, data.Interface()) == nil {
+				if !entry.Pointer {
+					data = data.Elem()
+				}
+				return ctx, &framework.OperatorResult{Data: data.Interface(), Response: withCacheHeader(entry.Response, "HIT")}
+			}
+		}
+	} else if err != redis.Nil {
+		log.Printf("Cache lookup for %s failed: %v", o.Name(), err)
+	}
+
+	newCtx, result := o.inner.Execute(ctx, r)
+	if result == nil || result.Error != nil {
+		return newCtx, result
+	}
+	// 设置 Cookie 的响应与用户会话相关，不缓存
+	if result.Response == nil || len(result.Response.Cookies) == 0 {
+		o.store(ctx, key, owner, responseType, result)
+		result.Response = withCacheHeader(result.Response, "MISS")
+	}
+	return newCtx, result
+}
+
+// responseType 内层算子对请求方法声明的响应类型，未声明时返回 nil
+func (o *CachedOperator) responseType(method string) reflect.Type {
+	schema := framework.SchemaFor(o.inner, method)
+	if schema == nil || schema.Response == nil {
+		return nil
+	}
+	return reflect.TypeOf(schema.Response)
+}
+
+// store 写入缓存并登记到各标签，失败只记录日志
+func (o *CachedOperator) store(ctx context.Context, key, owner string, responseType reflect.Type, result *framework.OperatorResult) {
+	// 返回的数据与声明类型不符时命中缓存无法还原，不缓存
+	pointer := reflect.TypeOf(result.Data) == reflect.PointerTo(responseType)
+	if !pointer && reflect.TypeOf(result.Data) != responseType {
+		log.Printf("Cache store for %s skipped: data type %T does not match declared %s", o.Name(), result.Data, responseType)
+		return
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return
+	}
+	entry, err := json.Marshal(cacheEntry{Data: data, Pointer: pointer, Response: result.Response})
+	if err != nil {
+		return
+	}
+
+	pipe := database.GetRedis().Client.TxPipeline()
+	pipe.Set(ctx, key, entry, o.config.TTL.Duration)
+	for _, tag := range o.config.Tags {
+		tagKey := cacheTagKey(tag, owner)
+		pipe.SAdd(ctx, tagKey, key)
+		pipe.Expire(ctx, tagKey, o.config.TTL.Duration)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Cache store for %s failed: %v", o.Name(), err)
+	}
+}
+
+// cacheKey 由路由、算子、缓存所有者与规范化后的请求（方法、路径、查询参数、请求体）组成
+func (o *CachedOperator) cacheKey(ctx context.Context, r *framework.Request, owner string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", r.Method, r.Path, r.Query.Encode())
+	hash.Write(canonicalJSON(framework.RequestBody(ctx)))
+	return fmt.Sprintf("op_cache:%s:%s:%s:%s", framework.RouteName(ctx), o.Name(), owner, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// InvalidatingOperator 内层算子执行成功后清除指定标签下的缓存，事务路由中在提交后清除
+type InvalidatingOperator struct {
+	inner  framework.Operator
+	config InvalidateConfig
+}
+
+// NewInvalidatingOperator 创建 invalidate 装饰器包装的算子
+func NewInvalidatingOperator(op framework.Operator, config map[string]interface{}) (framework.Operator, error) {
+	var cfg InvalidateConfig
+	if err := framework.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if len(cfg.Tags) == 0 {
+		return nil, fmt.Errorf("invalidate requires at least one tag")
+	}
+	if err := validateCacheScope(cfg.Scope); err != nil {
+		return nil, err
+	}
+	return &InvalidatingOperator{inner: op, config: cfg}, nil
+}
+
+func (o *InvalidatingOperator) Name() string {
+	return o.inner.Name()
+}
+
+func (o *InvalidatingOperator) Unwrap() framework.Operator {
+	return o.inner
+}
+
+func (o *InvalidatingOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	newCtx, result := o.inner.Execute(ctx, r)
+	if result == nil || result.Error != nil {
+		return newCtx, result
+	}
+
+	owner, ok := cacheOwner(ctx, o.config.Scope)
+	if !ok || database.GetRedis() == nil {
+		return newCtx, result
+	}
+
+	// 事务路由中写入在提交后才对其它请求可见，清除缓存也推迟到提交之后
+	framework.AfterCommit(ctx, func(ctx context.Context) {
+		o.purge(ctx, owner)
+	})
+	return newCtx, result
+}
+
+// purge 清除各标签下的缓存，失败只记录日志
+func (o *InvalidatingOperator) purge(ctx context.Context, owner string) {
+	rdb := database.GetRedis().Client
+	for _, tag := range o.config.Tags {
+		tagKey := cacheTagKey(tag, owner)
+		keys, err := rdb.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			log.Printf("Cache invalidation for tag %s failed: %v", tag, err)
+			continue
+		}
+		if err := rdb.Del(ctx, append(keys, tagKey)...).Err(); err != nil {
+			log.Printf("Cache invalidation for tag %s failed: %v", tag, err)
+		}
+	}
+}
+
+func validateCacheScope(scope string) error {
+	switch scope {
+	case "", cacheScopeUser, cacheScopeGlobal:
+		return nil
+	}
+	return fmt.Errorf("unknown cache scope %s", scope)
+}
+
+// cacheOwner 返回缓存所有者：user 范围为当前用户ID，未认证时不使用缓存
+func cacheOwner(ctx context.Context, scope string) (string, bool) {
+	if scope == cacheScopeGlobal {
+		return "_", true
+	}
+	user, ok := framework.Get(ctx, UserKey)
+	if !ok || user == nil {
+		return "", false
+	}
+	return user.ID, true
+}
+
+func cacheTagKey(tag, owner string) string {
+	return "op_cache_tag:" + tag + ":" + owner
+}
+
+func withCacheHeader(resp *framework.Response, status string) *framework.Response {
+	if resp == nil {
+		resp = framework.NewResponse(0)
+	}
+	return resp.SetHeader("X-Cache", status)
+}
+
+// canonicalJSON 规范化 JSON 请求体（对象键排序、去除空白），非 JSON 内容原样返回
+func canonicalJSON(body []byte) []byte {
+	var value interface{}
+	if len(bytes.TrimSpace(body)) == 0 || json.Unmarshal(body, &value) != nil {
+		return body
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
@@ -0,0 +1,284 @@
+package operators
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/zhanghuachuan/water-reminder/database"
+	"github.com/zhanghuachuan/water-reminder/framework"
+)
+
+// cacheTestStats 测试算子声明的响应类型
+type cacheTestStats struct {
+	Calls int64  `json:"calls"`
+	Query string `json:"query"`
+}
+
+// statsOperator 声明了 GET 响应类型的测试算子，记录实际执行次数
+type statsOperator struct {
+	name    string
+	pointer bool // 返回声明类型的指针
+	calls   atomic.Int64
+}
+
+func (o *statsOperator) Name() string { return o.name }
+
+func (o *statsOperator) Execute(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+	stats := cacheTestStats{Calls: o.calls.Add(1), Query: req.Query.Get("q")}
+	if o.pointer {
+		return ctx, &framework.OperatorResult{Data: &stats}
+	}
+	return ctx, &framework.OperatorResult{Data: stats}
+}
+
+func (o *statsOperator) Schema(method string) *framework.OperatorSchema {
+	if method != http.MethodGet {
+		return nil
+	}
+	return &framework.OperatorSchema{Response: cacheTestStats{}}
+}
+
+var (
+	cacheTestValue   = &statsOperator{name: "operators_test_stats"}
+	cacheTestPointer = &statsOperator{name: "operators_test_stats_ptr", pointer: true}
+)
+
+func init() {
+	framework.RegisterOperator(cacheTestValue.name, cacheTestValue)
+	framework.RegisterOperator(cacheTestPointer.name, cacheTestPointer)
+	framework.RegisterOperator("operators_test_write", &testOperator{name: "operators_test_write",
+		execute: func(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+			return ctx, &framework.OperatorResult{Data: "written"}
+		}})
+}
+
+// mockRedis 将全局 Redis 替换为内存实现，测试结束时恢复
+func mockRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	previous := database.Redis
+	database.Redis = &database.RedisClient{Client: client}
+	t.Cleanup(func() {
+		database.Redis = previous
+		client.Close()
+	})
+	return server
+}
+
+// runGet 以 GET 执行路由，返回响应数据与 X-Cache 头
+func runGet(t *testing.T, s *framework.Scheduler, serverName, query string) (interface{}, string) {
+	t.Helper()
+	request := framework.NewRequest(http.MethodGet, serverName, nil)
+	request.Query.Set("q", query)
+	output, err := s.Run(context.Background(), serverName, framework.ExecuteOptions{Request: request})
+	if err != nil {
+		t.Fatalf("Run %s: %v", serverName, err)
+	}
+	cacheStatus := ""
+	if output.Response != nil {
+		cacheStatus = output.Response.Header.Get("X-Cache")
+	}
+	return output.Body, cacheStatus
+}
+
+func TestCacheDecorator(t *testing.T) {
+	mockRedis(t)
+	s := newTestScheduler(t, `[
+	  {"server_name": "/stats", "response": {"from": "stats"}, "nodes": [
+	    {"name": "operators_test_user", "downstream": "stats"},
+	    {"name": "stats", "operator": "operators_test_stats", "wrap": [{"name": "cache", "config": {"ttl": "60s", "tags": ["stats"]}}]}
+	  ]},
+	  {"server_name": "/stats_ptr", "response": {"from": "stats"}, "nodes": [
+	    {"name": "operators_test_user", "downstream": "stats"},
+	    {"name": "stats", "operator": "operators_test_stats_ptr", "wrap": [{"name": "cache", "config": {"ttl": "60s"}}]}
+	  ]},
+	  {"server_name": "/anonymous", "nodes": [
+	    {"name": "stats", "operator": "operators_test_stats", "wrap": [{"name": "cache", "config": {"ttl": "60s"}}]}
+	  ]},
+	  {"server_name": "/global", "nodes": [
+	    {"name": "stats", "operator": "operators_test_stats", "wrap": [{"name": "cache", "config": {"ttl": "60s", "scope": "global"}}]}
+	  ]}
+	]`)
+
+	tests := []struct {
+		name       string
+		route      string
+		query      string
+		wantStatus string // X-Cache，绕过缓存时为空
+		wantBody   interface{}
+	}{
+		{name: "first request misses", route: "/stats", query: "a", wantStatus: "MISS", wantBody: cacheTestStats{Calls: 1, Query: "a"}},
+		{name: "same request hits and decodes the declared type", route: "/stats", query: "a", wantStatus: "HIT", wantBody: cacheTestStats{Calls: 1, Query: "a"}},
+		{name: "different query misses", route: "/stats", query: "b", wantStatus: "MISS", wantBody: cacheTestStats{Calls: 2, Query: "b"}},
+		{name: "pointer result misses", route: "/stats_ptr", query: "a", wantStatus: "MISS", wantBody: &cacheTestStats{Calls: 1, Query: "a"}},
+		{name: "pointer result hits as a pointer", route: "/stats_ptr", query: "a", wantStatus: "HIT", wantBody: &cacheTestStats{Calls: 1, Query: "a"}},
+		// user 范围的缓存在未认证时不生效
+		{name: "anonymous request bypasses", route: "/anonymous", query: "a", wantBody: cacheTestStats{Calls: 3, Query: "a"}},
+		{name: "anonymous request bypasses again", route: "/anonymous", query: "a", wantBody: cacheTestStats{Calls: 4, Query: "a"}},
+		{name: "global scope misses without a user", route: "/global", query: "a", wantStatus: "MISS", wantBody: cacheTestStats{Calls: 5, Query: "a"}},
+		{name: "global scope hits without a user", route: "/global", query: "a", wantStatus: "HIT", wantBody: cacheTestStats{Calls: 5, Query: "a"}},
+	}
+	// 用例按顺序共享缓存与调用计数
+	cacheTestValue.calls.Store(0)
+	cacheTestPointer.calls.Store(0)
+	for _, tt := range tests {
+		body, status := runGet(t, s, tt.route, tt.query)
+		if status != tt.wantStatus {
+			t.Errorf("%s: X-Cache = %q, want %q", tt.name, status, tt.wantStatus)
+		}
+		switch want := tt.wantBody.(type) {
+		case *cacheTestStats:
+			got, ok := body.(*cacheTestStats)
+			if !ok || *got != *want {
+				t.Errorf("%s: body = %#v, want %#v", tt.name, body, want)
+			}
+		default:
+			if body != want {
+				t.Errorf("%s: body = %#v, want %#v", tt.name, body, want)
+			}
+		}
+	}
+}
+
+func TestInvalidateDecorator(t *testing.T) {
+	server := mockRedis(t)
+	const stats = `{"server_name": "/stats", "nodes": [
+	    {"name": "operators_test_user", "downstream": "stats"},
+	    {"name": "stats", "operator": "operators_test_stats", "wrap": [{"name": "cache", "config": {"ttl": "60s", "tags": ["stats"]}}]}
+	  ]}`
+
+	var tagDuringRoute atomic.Bool
+	framework.RegisterOperator("operators_test_tag_probe", &testOperator{name: "operators_test_tag_probe",
+		execute: func(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+			tagDuringRoute.Store(server.Exists(cacheTagKey("stats", "u1")))
+			return ctx, &framework.OperatorResult{}
+		}})
+
+	tests := []struct {
+		name           string
+		write          string
+		wantTx         func(mock sqlmock.Sqlmock)
+		wantWriteErr   bool
+		wantTagInRoute bool   // 写路由后续节点执行时标签是否仍存在
+		wantStatus     string // 写路由之后再次读取的 X-Cache
+	}{
+		{
+			name: "purged immediately outside a transaction",
+			write: `{"server_name": "/write", "nodes": [
+			    {"name": "operators_test_user", "downstream": "write"},
+			    {"name": "write", "operator": "operators_test_write", "wrap": [{"name": "invalidate", "config": {"tags": ["stats"]}}], "downstream": "operators_test_tag_probe"},
+			    {"name": "operators_test_tag_probe"}
+			  ]}`,
+			wantStatus: "MISS",
+		},
+		{
+			name: "purged after commit",
+			write: `{"server_name": "/write", "transactional": true, "nodes": [
+			    {"name": "operators_test_user", "downstream": "write"},
+			    {"name": "write", "operator": "operators_test_write", "wrap": [{"name": "invalidate", "config": {"tags": ["stats"]}}], "downstream": "operators_test_tag_probe"},
+			    {"name": "operators_test_tag_probe"}
+			  ]}`,
+			wantTx:         func(mock sqlmock.Sqlmock) { mock.ExpectBegin(); mock.ExpectCommit() },
+			wantTagInRoute: true,
+			wantStatus:     "MISS",
+		},
+		{
+			name: "kept on rollback",
+			write: `{"server_name": "/write", "transactional": true, "nodes": [
+			    {"name": "operators_test_user", "downstream": "write"},
+			    {"name": "write", "operator": "operators_test_write", "wrap": [{"name": "invalidate", "config": {"tags": ["stats"]}}], "downstream": "operators_test_fail"},
+			    {"name": "operators_test_fail"}
+			  ]}`,
+			wantTx:       func(mock sqlmock.Sqlmock) { mock.ExpectBegin(); mock.ExpectRollback() },
+			wantWriteErr: true,
+			wantStatus:   "HIT",
+		},
+		{
+			name: "other tags are kept",
+			write: `{"server_name": "/write", "nodes": [
+			    {"name": "operators_test_user", "downstream": "write"},
+			    {"name": "write", "operator": "operators_test_write", "wrap": [{"name": "invalidate", "config": {"tags": ["reminders"]}}]}
+			  ]}`,
+			wantStatus: "HIT",
+		},
+		{
+			name: "global scope does not purge user caches",
+			write: `{"server_name": "/write", "nodes": [
+			    {"name": "operators_test_user", "downstream": "write"},
+			    {"name": "write", "operator": "operators_test_write", "wrap": [{"name": "invalidate", "config": {"tags": ["stats"], "scope": "global"}}]}
+			  ]}`,
+			wantStatus: "HIT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.FlushAll()
+			tagDuringRoute.Store(false)
+			mock := mockDB(t)
+			s := newTestScheduler(t, "["+stats+","+tt.write+"]")
+
+			if _, status := runGet(t, s, "/stats", "a"); status != "MISS" {
+				t.Fatalf("first read X-Cache = %q, want MISS", status)
+			}
+
+			if tt.wantTx != nil {
+				tt.wantTx(mock)
+			}
+			request := framework.NewRequest(http.MethodPost, "/write", nil)
+			if _, err := s.Run(context.Background(), "/write", framework.ExecuteOptions{Request: request}); (err != nil) != tt.wantWriteErr {
+				t.Fatalf("write error = %v, want error %v", err, tt.wantWriteErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("transaction: %v", err)
+			}
+			if tagDuringRoute.Load() != tt.wantTagInRoute {
+				t.Errorf("tag present while the write route ran = %v, want %v", tagDuringRoute.Load(), tt.wantTagInRoute)
+			}
+
+			if _, status := runGet(t, s, "/stats", "a"); status != tt.wantStatus {
+				t.Errorf("read after write X-Cache = %q, want %q", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCacheDecoratorConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrap    string
+		wantErr string
+	}{
+		{name: "cache without ttl", wrap: `{"name": "cache", "config": {"tags": ["stats"]}}`, wantErr: "cache ttl must be positive"},
+		{name: "cache with unknown scope", wrap: `{"name": "cache", "config": {"ttl": "1s", "scope": "team"}}`, wantErr: "unknown cache scope team"},
+		{name: "invalidate without tags", wrap: `{"name": "invalidate", "config": {}}`, wantErr: "invalidate requires at least one tag"},
+		{name: "invalidate with unknown scope", wrap: `{"name": "invalidate", "config": {"tags": ["stats"], "scope": "team"}}`, wantErr: "unknown cache scope team"},
+		{name: "valid cache", wrap: `{"name": "cache", "config": {"ttl": "1s"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := framework.NewScheduler().LoadConfig(writeConfig(t, `[{"server_name": "/a", "nodes": [{"name": "operators_test_stats", "wrap": [`+tt.wrap+`]}]}]`))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadConfig error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 算子未声明响应类型时命中缓存无法还原数据
+	err := framework.NewScheduler().LoadConfig(writeConfig(t, `[{"server_name": "/a", "nodes": [{"name": "operators_test_write", "wrap": [{"name": "cache", "config": {"ttl": "1s"}}]}]}]`))
+	if err == nil || !strings.Contains(err.Error(), "cache requires operator operators_test_write to declare a response type") {
+		t.Errorf("LoadConfig error = %v, want the missing response type", err)
+	}
+}
@@ -2,13 +2,15 @@ package operators
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/zhanghuachuan/water-reminder/database"
 	"github.com/zhanghuachuan/water-reminder/framework"
 	"github.com/zhanghuachuan/water-reminder/types"
+	"gorm.io/gorm"
 )
 
 type DrinkingRecordOperator struct{}
@@ -17,17 +19,45 @@ func (o *DrinkingRecordOperator) Name() string {
 	return "drinking-record"
 }
 
-func (o *DrinkingRecordOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// Schema POST 创建饮水记录，GET 按路径参数 id 查询单条记录
+func (o *DrinkingRecordOperator) Schema(method string) *framework.OperatorSchema {
+	switch method {
+	case http.MethodPost:
+		return &framework.OperatorSchema{
+			Summary:  "创建饮水记录",
+			Request:  types.WaterRecord{},
+			Response: types.WaterRecord{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		}
+	case http.MethodGet:
+		return &framework.OperatorSchema{
+			Summary:  "查询饮水记录",
+			Response: types.WaterRecord{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusNotFound},
+		}
+	default:
+		return nil
+	}
+}
+
+func (o *DrinkingRecordOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	println("开始执行算子:", o.Name())
 	println("请求方法:", r.Method)
-	println("请求URL:", r.URL.String())
+	println("请求URL:", r.Path)
 
-	user := ctx.Value("user").(*types.User)
+	user, ok := framework.Get(ctx, UserKey)
+	if !ok || user == nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("Unauthorized", "Unauthorized", http.StatusUnauthorized),
+		}
+	}
 	println("用户信息:", user.ID, user.Username)
 
 	switch r.Method {
 	case http.MethodPost:
 		return o.handleCreateRecord(ctx, r, user)
+	case http.MethodGet:
+		return o.handleGetRecord(ctx, r, user)
 	default:
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Method not allowed", "MethodNotAllowed", http.StatusMethodNotAllowed),
@@ -35,12 +65,10 @@ func (o *DrinkingRecordOperator) Execute(ctx context.Context, r *http.Request) (
 	}
 }
 
-func (o *DrinkingRecordOperator) handleCreateRecord(ctx context.Context, r *http.Request, user *types.User) (context.Context, *framework.OperatorResult) {
+func (o *DrinkingRecordOperator) handleCreateRecord(ctx context.Context, r *framework.Request, user *types.User) (context.Context, *framework.OperatorResult) {
 	var record types.WaterRecord
-	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Invalid request", "BadRequest", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &record, framework.DecodeOptions{}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 设置用户ID和时间
@@ -50,13 +78,56 @@ func (o *DrinkingRecordOperator) handleCreateRecord(ctx context.Context, r *http
 	}
 
 	// 保存到数据库
-	if err := database.GetDB().Create(&record).Error; err != nil {
+	if err := dbFor(ctx).Create(&record).Error; err != nil {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Failed to save record", "InternalServerError", http.StatusInternalServerError),
 		}
 	}
 
+	// 事务回滚时记录不存在，业务指标在提交后再记录
+	framework.AfterCommit(ctx, func(context.Context) { observeWaterRecord(record) })
+
+	return ctx, &framework.OperatorResult{
+		Data: record,
+		Response: framework.NewResponse(http.StatusCreated).
+			SetHeader("Location", fmt.Sprintf("/records/%d", record.ID)),
+	}
+}
+
+// handleGetRecord 查询当前用户的单条记录，创建记录时返回的 Location 指向该接口
+func (o *DrinkingRecordOperator) handleGetRecord(ctx context.Context, r *framework.Request, user *types.User) (context.Context, *framework.OperatorResult) {
+	id, err := strconv.ParseUint(framework.PathParam(ctx, "id"), 10, 64)
+	if err != nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("Record not found", "NotFound", http.StatusNotFound),
+		}
+	}
+
+	var record types.WaterRecord
+	if err := dbFor(ctx).Where("id = ? AND user_id = ?", id, user.ID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, &framework.OperatorResult{
+				Error: types.NewApiError("Record not found", "NotFound", http.StatusNotFound),
+			}
+		}
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("Failed to load record", "InternalServerError", http.StatusInternalServerError),
+		}
+	}
+
 	return ctx, &framework.OperatorResult{
 		Data: record,
 	}
 }
+
+// Compensate 路由后续节点失败时删除本算子创建的饮水记录；GET 返回的是已有记录，不做处理
+func (o *DrinkingRecordOperator) Compensate(ctx context.Context, r *framework.Request, data interface{}) error {
+	if r.Method != http.MethodPost {
+		return nil
+	}
+	record, ok := data.(types.WaterRecord)
+	if !ok || record.ID == 0 {
+		return nil
+	}
+	return dbFor(ctx).Delete(&types.WaterRecord{}, record.ID).Error
+}
@@ -0,0 +1,162 @@
+package operators
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/zhanghuachuan/water-reminder/database"
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testOperator 以函数实现的测试算子
+type testOperator struct {
+	name    string
+	execute func(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult)
+}
+
+func (o *testOperator) Name() string { return o.name }
+
+func (o *testOperator) Execute(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+	return o.execute(ctx, req)
+}
+
+func init() {
+	framework.RegisterOperator("operators_test_user", &testOperator{name: "operators_test_user",
+		execute: func(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+			if err := framework.Set(ctx, UserKey, &types.User{ID: "u1"}); err != nil {
+				return ctx, &framework.OperatorResult{Error: err}
+			}
+			return ctx, &framework.OperatorResult{}
+		}})
+	framework.RegisterOperator("operators_test_fail", &testOperator{name: "operators_test_fail",
+		execute: func(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+			return ctx, &framework.OperatorResult{Error: types.NewApiError("下游失败", "downstream failed", http.StatusBadGateway)}
+		}})
+}
+
+// mockDB 将全局数据库替换为 sqlmock 连接，测试结束时恢复
+func mockDB(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		sqlDB.Close()
+	})
+	return mock
+}
+
+// writeConfig 将调度配置写入临时文件并返回路径
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scheduler_config.json")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newTestScheduler 按 config 加载路由
+func newTestScheduler(t *testing.T, config string) *framework.Scheduler {
+	t.Helper()
+	s := framework.NewScheduler()
+	if err := s.LoadConfig(writeConfig(t, config)); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return s
+}
+
+func TestDrinkingRecordCompensation(t *testing.T) {
+	s := newTestScheduler(t, `[{
+	  "server_name": "/records",
+	  "path": "/records/{id}",
+	  "nodes": [
+	    {"name": "operators_test_user", "downstream": "drinking-record"},
+	    {"name": "drinking-record", "downstream": "operators_test_fail"},
+	    {"name": "operators_test_fail"}
+	  ]
+	}]`)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		expect func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "get leaves the existing row intact",
+			method: http.MethodGet,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT \\* FROM `water_records`").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "record_time", "action", "reminder_id", "created_at"}).
+						AddRow(7, "u1", 250, time.Now(), "drank", "r1", time.Now()))
+				// 不应有 DELETE：未满足的调用会使 ExpectationsWereMet 或补偿失败
+			},
+		},
+		{
+			name:   "post deletes the created row",
+			method: http.MethodPost,
+			body:   `{"amount": 250, "action": "drank", "reminderId": "r1"}`,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `water_records`").WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM `water_records` WHERE `water_records`.`id` = \\?").
+					WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mockDB(t)
+			tt.expect(mock)
+
+			var body []byte
+			if tt.body != "" {
+				body = []byte(tt.body)
+			}
+			req := framework.NewRPCRequest(tt.method, "/records/7", map[string]string{"Content-Type": "application/json"}, body)
+			req.Params = map[string]string{"id": "7"}
+			output, err := s.Run(context.Background(), "/records", framework.ExecuteOptions{Request: req})
+			if err == nil {
+				t.Fatal("route must fail at the downstream node")
+			}
+
+			var record *framework.ExecutionResult
+			for i := range output.Results {
+				if output.Results[i].OperatorName == "drinking-record" {
+					record = &output.Results[i]
+				}
+			}
+			if record == nil || !record.Success {
+				t.Fatalf("drinking-record result = %+v", record)
+			}
+			if record.CompensationError != nil {
+				t.Errorf("compensation error: %v", record.CompensationError)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
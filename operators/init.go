@@ -27,5 +27,17 @@ func InitializeOperators() {
 	framework.RegisterOperator("drinking-record", &DrinkingRecordOperator{})
 	framework.RegisterOperator("statistics", &StatisticsOperator{})
 
+	// 注册算子工厂，路由节点可通过 factory + config 创建独立配置的实例
+	framework.RegisterOperatorFactory("validate", framework.NewOperatorFactory("validate", NewValidatorOperator))
+	framework.RegisterOperatorFactory("rate_limit", framework.NewNodeOperatorFactory("rate_limit", NewRateLimitOperator))
+	framework.RegisterOperatorFactory("validate_schema", framework.NewOperatorFactory("validate_schema", NewSchemaValidatorOperator))
+
+	// 注册算子装饰器，节点可通过 wrap 为任意算子增加缓存与缓存失效
+	framework.RegisterOperatorDecorator("cache", framework.NewOperatorDecorator("cache", NewCachedOperator))
+	framework.RegisterOperatorDecorator("invalidate", framework.NewOperatorDecorator("invalidate", NewInvalidatingOperator))
+
+	// transactional 路由使用 GORM 事务
+	framework.SetTransactionBeginner(beginTransaction)
+
 	initialized = true
 }
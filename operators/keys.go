@@ -0,0 +1,9 @@
+package operators
+
+import (
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// UserKey 当前请求已认证的用户，由 auth/login/register 算子写入
+var UserKey = framework.NewKey[*types.User]("user")
@@ -2,7 +2,6 @@ package operators
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"time"
 
@@ -19,16 +18,24 @@ func (o *LoginOperator) Name() string {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" schema:"required,format=email"`
+	Password string `json:"password" schema:"required,minLength=1"`
 }
 
-func (o *LoginOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// Schema 登录接口的请求与响应
+func (o *LoginOperator) Schema(method string) *framework.OperatorSchema {
+	return &framework.OperatorSchema{
+		Summary:  "用户登录",
+		Request:  LoginRequest{},
+		Response: types.LoginResponseData{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	}
+}
+
+func (o *LoginOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("请求参数错误", "Invalid request body", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &req, framework.DecodeOptions{}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 验证用户凭据
@@ -65,11 +72,13 @@ func (o *LoginOperator) Execute(ctx context.Context, r *http.Request) (context.C
 		},
 	}
 
-	return context.WithValue(ctx, "user", &types.User{
-			ID:       user.ID,
-			Email:    user.Email,
-			Username: user.Username,
-		}), &framework.OperatorResult{
-			Data: loginData,
+	if err := framework.Set(ctx, UserKey, loginData.User); err != nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("请求状态不可用", err.Error(), http.StatusInternalServerError),
 		}
+	}
+
+	return ctx, &framework.OperatorResult{
+		Data: loginData,
+	}
 }
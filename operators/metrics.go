@@ -0,0 +1,82 @@
+package operators
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/metrics"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// activeUserWindow 最近多长时间内通过认证的用户视为活跃用户
+const activeUserWindow = 15 * time.Minute
+
+// 业务指标，与调度器指标一起通过 /metrics 导出
+var (
+	waterLogged = metrics.NewCounter("water_reminder_water_logged_ml_total",
+		"Millilitres of water logged by users, by record action.", "action")
+	waterRecords = metrics.NewCounter("water_reminder_water_records_total",
+		"Water records created, by record action.", "action")
+
+	activeUsers = newActiveUserTracker()
+)
+
+func init() {
+	metrics.NewGaugeFunc("water_reminder_active_users",
+		"Distinct users authenticated within the last 15 minutes.", activeUsers.count)
+}
+
+// observeWaterRecord 记录一条饮水记录；action 来自客户端，未知取值归为 other 以限制标签基数
+func observeWaterRecord(record types.WaterRecord) {
+	action := record.Action
+	if action != "drank" && action != "skipped" {
+		action = "other"
+	}
+	waterRecords.Inc(action)
+	if record.Amount > 0 {
+		waterLogged.Add(float64(record.Amount), action)
+	}
+}
+
+// activeUserTracker 记录用户最近一次认证的时间
+type activeUserTracker struct {
+	mu        sync.Mutex
+	lastSeen  map[string]time.Time
+	lastPrune time.Time
+}
+
+func newActiveUserTracker() *activeUserTracker {
+	return &activeUserTracker{lastSeen: make(map[string]time.Time)}
+}
+
+// seen 记录一次认证；每隔一分钟顺带清理过期记录，未被抓取时内存也不会无限增长
+func (t *activeUserTracker) seen(userID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.lastSeen[userID] = now
+	if now.Sub(t.lastPrune) >= time.Minute {
+		t.prune(now)
+	}
+}
+
+// count 返回窗口内的活跃用户数
+func (t *activeUserTracker) count() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(time.Now())
+	return float64(len(t.lastSeen))
+}
+
+// prune 删除窗口外的记录，调用方需持有锁
+func (t *activeUserTracker) prune(now time.Time) {
+	cutoff := now.Add(-activeUserWindow)
+	for userID, at := range t.lastSeen {
+		if at.Before(cutoff) {
+			delete(t.lastSeen, userID)
+		}
+	}
+	t.lastPrune = now
+}
@@ -0,0 +1,185 @@
+package operators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+const maxRateLimitBuckets = 10000
+
+// RateLimitOperator 按客户端限流的令牌桶算子，令牌桶保存在按限流器名称索引的注册表中
+type RateLimitOperator struct {
+	limiter *rateLimiter
+}
+
+// rateLimiter 一组客户端令牌桶，配置重载后同名限流器沿用原有计数
+type rateLimiter struct {
+	mu                sync.Mutex
+	shared            bool // 通过 limiter 显式命名，可被多个节点共享
+	requestsPerMinute int
+	rate              float64 // 每秒补充的令牌数
+	burst             float64
+	buckets           map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+var (
+	rateLimiters     = make(map[string]*rateLimiter)
+	rateLimiterMutex sync.Mutex
+)
+
+// RateLimitConfig 限流算子的节点配置
+type RateLimitConfig struct {
+	Limiter           string `json:"limiter"` // 共享的限流器名称，同名节点共享令牌桶，须配置相同的速率；默认每个路由节点独立
+	RequestsPerMinute int    `json:"requests_per_minute"`
+	Burst             int    `json:"burst"`
+}
+
+// NewRateLimitOperator 按节点配置创建限流算子
+func NewRateLimitOperator(serverName, nodeID string, config map[string]interface{}) (framework.Operator, error) {
+	var cfg RateLimitConfig
+	if err := framework.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.RequestsPerMinute <= 0 {
+		return nil, errors.New("requests_per_minute must be positive")
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.RequestsPerMinute
+	}
+
+	name, shared := cfg.Limiter, cfg.Limiter != ""
+	if !shared {
+		name = serverName + "/" + nodeID
+	}
+	limiter, err := getRateLimiter(name, shared, cfg.RequestsPerMinute, cfg.Burst)
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitOperator{limiter: limiter}, nil
+}
+
+// getRateLimiter 按名称获取限流器，不存在时创建。
+// 节点独立的限流器只属于一个节点，重载时更新速率并保留各客户端的令牌；
+// 共享限流器的速率必须与已有配置一致，避免一个路由的配置悄悄改变另一个路由的限流
+func getRateLimiter(name string, shared bool, requestsPerMinute, burst int) (*rateLimiter, error) {
+	rateLimiterMutex.Lock()
+	defer rateLimiterMutex.Unlock()
+
+	limiter, exists := rateLimiters[name]
+	if !exists {
+		limiter = &rateLimiter{shared: shared, buckets: make(map[string]*tokenBucket)}
+		limiter.configure(requestsPerMinute, burst)
+		rateLimiters[name] = limiter
+		return limiter, nil
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if shared || limiter.shared {
+		if limiter.shared != shared || limiter.requestsPerMinute != requestsPerMinute || limiter.burst != float64(burst) {
+			return nil, fmt.Errorf("rate limiter %s is already configured with requests_per_minute %d and burst %d",
+				name, limiter.requestsPerMinute, int(limiter.burst))
+		}
+		return limiter, nil
+	}
+	limiter.configure(requestsPerMinute, burst)
+	return limiter, nil
+}
+
+// configure 设置速率，调用方需持有锁或独占限流器
+func (l *rateLimiter) configure(requestsPerMinute, burst int) {
+	l.requestsPerMinute = requestsPerMinute
+	l.rate = float64(requestsPerMinute) / 60
+	l.burst = float64(burst)
+}
+
+func (o *RateLimitOperator) Name() string {
+	return "rate_limit"
+}
+
+// Schema 限流算子只声明可能返回的错误
+func (o *RateLimitOperator) Schema(method string) *framework.OperatorSchema {
+	return &framework.OperatorSchema{Errors: []int{http.StatusTooManyRequests}}
+}
+
+func (o *RateLimitOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	if !o.limiter.allow(clientKey(r), time.Now()) {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("请求过于频繁", "Too many requests", http.StatusTooManyRequests),
+		}
+	}
+	return ctx, &framework.OperatorResult{}
+}
+
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		l.pruneIdle(now)
+		bucket = &tokenBucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = bucket
+	}
+
+	// 按时间补充令牌
+	bucket.tokens += now.Sub(bucket.lastSeen).Seconds() * l.rate
+	if bucket.tokens > l.burst {
+		bucket.tokens = l.burst
+	}
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// pruneIdle 清理令牌已回满的空闲桶，避免客户端过多时内存无限增长
+func (l *rateLimiter) pruneIdle(now time.Time) {
+	if len(l.buckets) < maxRateLimitBuckets {
+		return
+	}
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientKey 限流使用的客户端标识：优先使用调用方地址（HTTP 与 gRPC 均由传输层设置），
+// 地址未知时按身份区分，令牌只保存摘要
+func clientKey(r *framework.Request) string {
+	if r.RemoteAddr != "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return "addr:" + r.RemoteAddr
+		}
+		return "addr:" + host
+	}
+	if r.Identity.Subject != "" {
+		return "subject:" + r.Identity.Subject
+	}
+	if r.Identity.Token != "" {
+		sum := sha256.Sum256([]byte(r.Identity.Token))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	return ""
+}
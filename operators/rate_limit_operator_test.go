@@ -0,0 +1,170 @@
+package operators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+func TestRateLimiterRegistry(t *testing.T) {
+	config := func(limiter string, rpm, burst int) map[string]interface{} {
+		cfg := map[string]interface{}{"requests_per_minute": rpm, "burst": burst}
+		if limiter != "" {
+			cfg["limiter"] = limiter
+		}
+		return cfg
+	}
+	newLimiter := func(t *testing.T, serverName, nodeID string, cfg map[string]interface{}) *rateLimiter {
+		t.Helper()
+		op, err := NewRateLimitOperator(serverName, nodeID, cfg)
+		if err != nil {
+			t.Fatalf("NewRateLimitOperator(%s, %s): %v", serverName, nodeID, err)
+		}
+		return op.(*RateLimitOperator).limiter
+	}
+
+	t.Run("same node ID on different routes", func(t *testing.T) {
+		a := newLimiter(t, "/rl_a", "rate_limit", config("", 60, 1))
+		b := newLimiter(t, "/rl_b", "rate_limit", config("", 60, 1))
+		if a == b {
+			t.Fatal("routes share a limiter")
+		}
+		now := time.Now()
+		if !a.allow("c", now) || !b.allow("c", now) {
+			t.Error("each route must have its own bucket")
+		}
+	})
+
+	t.Run("reload keeps tokens and applies the new rate", func(t *testing.T) {
+		before := newLimiter(t, "/rl_reload", "rate_limit", config("", 60, 1))
+		now := time.Now()
+		if !before.allow("c", now) {
+			t.Fatal("first request rejected")
+		}
+		after := newLimiter(t, "/rl_reload", "rate_limit", config("", 120, 1))
+		if after != before {
+			t.Fatal("reload created a new limiter")
+		}
+		if after.allow("c", now) {
+			t.Error("reload reset the bucket")
+		}
+		if after.requestsPerMinute != 120 {
+			t.Errorf("requests_per_minute = %d, want 120", after.requestsPerMinute)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		second  map[string]interface{}
+		wantErr string
+	}{
+		{name: "same config shares the limiter", second: config("rl_shared_ok", 10, 5)},
+		{name: "different rate", second: config("rl_shared_rate", 20, 5), wantErr: "requests_per_minute 10 and burst 5"},
+		{name: "different burst", second: config("rl_shared_burst", 10, 6), wantErr: "requests_per_minute 10 and burst 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.second["limiter"].(string)
+			first := newLimiter(t, "/rl_first", "rate_limit", config(name, 10, 5))
+			op, err := NewRateLimitOperator("/rl_second", "rate_limit", tt.second)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr == "" && op.(*RateLimitOperator).limiter != first:
+				t.Error("nodes with the same limiter name must share it")
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if first.requestsPerMinute != 10 || first.burst != 5 {
+				t.Errorf("shared limiter changed to %d/min burst %v", first.requestsPerMinute, first.burst)
+			}
+		})
+	}
+}
+
+func TestRateLimitPerCaller(t *testing.T) {
+	httpRequest := func(remoteAddr, authorization string) *framework.Request {
+		r := httptest.NewRequest(http.MethodGet, "/statistics", nil)
+		r.RemoteAddr = remoteAddr
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		return framework.NewHTTPRequest(r)
+	}
+	subject := func(userID string) *framework.Request {
+		r := framework.NewRequest(http.MethodGet, "/statistics", nil)
+		r.Identity.Subject = userID
+		return r
+	}
+	rpc := func(token string) *framework.Request {
+		return framework.NewRPCRequest(http.MethodGet, "/statistics", map[string]string{"authorization": "Bearer " + token}, nil)
+	}
+
+	keys := []struct {
+		name    string
+		req     *framework.Request
+		wantKey string
+	}{
+		{name: "address without port", req: httpRequest("10.0.0.1:5423", ""), wantKey: "addr:10.0.0.1"},
+		{name: "ipv6 address", req: httpRequest("[::1]:5423", ""), wantKey: "addr:::1"},
+		{name: "address wins over token", req: httpRequest("10.0.0.1:1", "Bearer t1"), wantKey: "addr:10.0.0.1"},
+		{name: "address that is not host:port", req: httpRequest("unix-socket", ""), wantKey: "addr:unix-socket"},
+		{name: "trusted subject", req: subject("u1"), wantKey: "subject:u1"},
+		{name: "token digest", req: rpc("t1"), wantKey: "token:" + sha256Hex("t1")},
+		{name: "anonymous", req: framework.NewRequest(http.MethodGet, "/statistics", nil), wantKey: ""},
+	}
+	for _, tt := range keys {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientKey(tt.req); got != tt.wantKey {
+				t.Errorf("clientKey = %q, want %q", got, tt.wantKey)
+			}
+		})
+	}
+
+	// 每个用例使用独立的限流器，burst 1：同一调用方第二次被拒，其他调用方不受影响
+	callers := []struct {
+		name   string
+		first  *framework.Request
+		second *framework.Request
+		shared bool // 两个请求属于同一调用方
+	}{
+		{name: "same address on different ports", first: httpRequest("10.0.0.1:1", ""), second: httpRequest("10.0.0.1:2", ""), shared: true},
+		{name: "different addresses", first: httpRequest("10.0.0.1:1", ""), second: httpRequest("10.0.0.2:1", "")},
+		{name: "different subjects", first: subject("u1"), second: subject("u2")},
+		{name: "same RPC token", first: rpc("t1"), second: rpc("t1"), shared: true},
+		{name: "different RPC tokens", first: rpc("t1"), second: rpc("t2")},
+		{name: "anonymous callers share a bucket", first: framework.NewRequest("", "/statistics", nil), second: framework.NewRequest("", "/statistics", nil), shared: true},
+	}
+	for i, tt := range callers {
+		t.Run(tt.name, func(t *testing.T) {
+			op, err := NewRateLimitOperator("/rl_callers", "rate_limit_"+strconv.Itoa(i), map[string]interface{}{"requests_per_minute": 1, "burst": 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, result := op.Execute(context.Background(), tt.first); result.Error != nil {
+				t.Fatalf("first caller rejected: %v", result.Error)
+			}
+			_, result := op.Execute(context.Background(), tt.second)
+			var apiErr *types.ApiError
+			limited := errors.As(result.Error, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+			if limited != tt.shared {
+				t.Errorf("second caller limited = %v, want %v (error %v)", limited, tt.shared, result.Error)
+			}
+		})
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
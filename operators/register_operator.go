@@ -2,7 +2,6 @@ package operators
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
 	"github.com/zhanghuachuan/water-reminder/framework"
@@ -21,17 +20,25 @@ func (o *RegisterOperator) Name() string {
 }
 
 type RegisterRequest struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Username string `json:"username" schema:"required,minLength=1"`
+	Email    string `json:"email" schema:"required,format=email"`
+	Password string `json:"password" schema:"required,minLength=1"`
 }
 
-func (o *RegisterOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// Schema 注册接口的请求与响应
+func (o *RegisterOperator) Schema(method string) *framework.OperatorSchema {
+	return &framework.OperatorSchema{
+		Summary:  "用户注册",
+		Request:  RegisterRequest{},
+		Response: types.LoginResponseData{},
+		Errors:   []int{http.StatusBadRequest},
+	}
+}
+
+func (o *RegisterOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("请求参数错误", "Invalid request body", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &req, framework.DecodeOptions{}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 验证输入
@@ -63,7 +70,22 @@ func (o *RegisterOperator) Execute(ctx context.Context, r *http.Request) (contex
 		User:  user,
 	}
 
-	return context.WithValue(ctx, "user", user), &framework.OperatorResult{
+	if err := framework.Set(ctx, UserKey, user); err != nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("请求状态不可用", err.Error(), http.StatusInternalServerError),
+		}
+	}
+
+	return ctx, &framework.OperatorResult{
 		Data: registerData,
 	}
 }
+
+// Compensate 路由后续节点失败时删除刚注册的用户，允许客户端使用同一邮箱重试
+func (o *RegisterOperator) Compensate(ctx context.Context, r *framework.Request, data interface{}) error {
+	registerData, ok := data.(*types.LoginResponseData)
+	if !ok || registerData.User == nil {
+		return nil
+	}
+	return dbFor(ctx).Delete(&types.User{}, "id = ?", registerData.User.ID).Error
+}
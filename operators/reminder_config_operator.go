@@ -2,10 +2,8 @@ package operators
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 
-	"github.com/zhanghuachuan/water-reminder/database"
 	"github.com/zhanghuachuan/water-reminder/framework"
 	"github.com/zhanghuachuan/water-reminder/types"
 )
@@ -22,8 +20,34 @@ func init() {
 	framework.RegisterOperator("reminder-config", &ReminderConfigOperator{})
 }
 
-func (o *ReminderConfigOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
-	user := ctx.Value("user").(*types.User)
+// Schema GET 查询提醒配置，POST/PUT 更新提醒配置
+func (o *ReminderConfigOperator) Schema(method string) *framework.OperatorSchema {
+	switch method {
+	case http.MethodGet:
+		return &framework.OperatorSchema{
+			Summary:  "查询提醒配置",
+			Response: types.ReminderConfig{},
+			Errors:   []int{http.StatusUnauthorized, http.StatusNotFound},
+		}
+	case http.MethodPost, http.MethodPut:
+		return &framework.OperatorSchema{
+			Summary:  "更新提醒配置",
+			Request:  types.ReminderConfig{},
+			Response: types.ReminderConfig{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		}
+	default:
+		return nil
+	}
+}
+
+func (o *ReminderConfigOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	user, ok := framework.Get(ctx, UserKey)
+	if !ok || user == nil {
+		return ctx, &framework.OperatorResult{
+			Error: types.NewApiError("Unauthorized", "Unauthorized", http.StatusUnauthorized),
+		}
+	}
 
 	switch r.Method {
 	case http.MethodGet:
@@ -37,10 +61,10 @@ func (o *ReminderConfigOperator) Execute(ctx context.Context, r *http.Request) (
 	}
 }
 
-func (o *ReminderConfigOperator) handleGetConfig(ctx context.Context, r *http.Request, user *types.User) (context.Context, *framework.OperatorResult) {
+func (o *ReminderConfigOperator) handleGetConfig(ctx context.Context, r *framework.Request, user *types.User) (context.Context, *framework.OperatorResult) {
 	// 从数据库获取用户配置
 	var config types.ReminderConfig
-	if err := database.GetDB().Where("user_id = ?", user.ID).First(&config).Error; err != nil {
+	if err := dbFor(ctx).Where("user_id = ?", user.ID).First(&config).Error; err != nil {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Config not found", "NotFound", http.StatusNotFound),
 		}
@@ -51,12 +75,10 @@ func (o *ReminderConfigOperator) handleGetConfig(ctx context.Context, r *http.Re
 	}
 }
 
-func (o *ReminderConfigOperator) handleUpdateConfig(ctx context.Context, r *http.Request, user *types.User) (context.Context, *framework.OperatorResult) {
+func (o *ReminderConfigOperator) handleUpdateConfig(ctx context.Context, r *framework.Request, user *types.User) (context.Context, *framework.OperatorResult) {
 	var config types.ReminderConfig
-	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Invalid request", "BadRequest", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &config, framework.DecodeOptions{}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 设置用户ID
@@ -75,7 +97,7 @@ func (o *ReminderConfigOperator) handleUpdateConfig(ctx context.Context, r *http
 	}
 
 	// 保存配置到数据库
-	if err := database.GetDB().Where("user_id = ?", user.ID).Save(&config).Error; err != nil {
+	if err := dbFor(ctx).Where("user_id = ?", user.ID).Save(&config).Error; err != nil {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Failed to save config", "InternalServerError", http.StatusInternalServerError),
 		}
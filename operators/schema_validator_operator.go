@@ -0,0 +1,144 @@
+package operators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+)
+
+// SchemaValidatorOperator 按 JSON Schema 校验请求体与查询参数，
+// 校验失败时返回 400，Details 中列出每个不合法的字段
+type SchemaValidatorOperator struct {
+	body           *framework.Schema
+	query          *framework.Schema
+	allowEmptyBody bool
+
+	// byMethod 复用的算子按请求方法声明的 schema，创建时编译
+	byMethod map[string]*methodSchemas
+}
+
+type methodSchemas struct {
+	body  *framework.Schema
+	query *framework.Schema
+}
+
+// schemaMethods 复用算子 schema 时按这些方法分别编译
+var schemaMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// SchemaValidatorConfig 校验算子的节点配置，body/query 与 schema_from 二选一
+type SchemaValidatorConfig struct {
+	Body           *framework.Schema `json:"body"`             // 请求体的 JSON Schema
+	Query          *framework.Schema `json:"query"`            // 查询参数的 JSON Schema，type 为 object
+	SchemaFrom     string            `json:"schema_from"`      // 复用已注册算子声明的 Request 与 Query
+	AllowEmptyBody bool              `json:"allow_empty_body"` // 请求体为空时跳过请求体校验
+}
+
+// NewSchemaValidatorOperator 按节点配置创建 JSON Schema 校验算子
+func NewSchemaValidatorOperator(config map[string]interface{}) (framework.Operator, error) {
+	var cfg SchemaValidatorConfig
+	if err := framework.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+
+	op := &SchemaValidatorOperator{allowEmptyBody: cfg.AllowEmptyBody}
+	if cfg.SchemaFrom != "" {
+		if cfg.Body != nil || cfg.Query != nil {
+			return nil, errors.New("schema_from cannot be combined with body or query")
+		}
+		target, err := framework.GetOperator(cfg.SchemaFrom)
+		if err != nil {
+			return nil, fmt.Errorf("schema_from %s: %w", cfg.SchemaFrom, err)
+		}
+		provider, ok := target.(framework.SchemaProvider)
+		if !ok {
+			return nil, fmt.Errorf("schema_from %s: operator does not declare a schema", cfg.SchemaFrom)
+		}
+		op.byMethod = make(map[string]*methodSchemas)
+		for _, method := range schemaMethods {
+			declared := provider.Schema(method)
+			if declared == nil {
+				continue
+			}
+			schemas := &methodSchemas{
+				body:  framework.SchemaOf(declared.Request),
+				query: framework.SchemaOf(declared.Query),
+			}
+			if err := schemas.body.Compile(); err != nil {
+				return nil, fmt.Errorf("schema_from %s: %s request schema: %w", cfg.SchemaFrom, method, err)
+			}
+			if err := schemas.query.Compile(); err != nil {
+				return nil, fmt.Errorf("schema_from %s: %s query schema: %w", cfg.SchemaFrom, method, err)
+			}
+			op.byMethod[method] = schemas
+		}
+		return op, nil
+	}
+
+	if cfg.Body == nil && cfg.Query == nil {
+		return nil, errors.New("body, query or schema_from is required")
+	}
+	if err := cfg.Body.Compile(); err != nil {
+		return nil, fmt.Errorf("body schema: %w", err)
+	}
+	if err := cfg.Query.Compile(); err != nil {
+		return nil, fmt.Errorf("query schema: %w", err)
+	}
+	op.body = cfg.Body
+	op.query = cfg.Query
+	return op, nil
+}
+
+func (o *SchemaValidatorOperator) Name() string {
+	return "validate_schema"
+}
+
+// Schema 配置中的 schema 作为接口的请求声明，OpenAPI 文档与校验保持一致
+func (o *SchemaValidatorOperator) Schema(method string) *framework.OperatorSchema {
+	schemas := o.schemasFor(method)
+	schema := &framework.OperatorSchema{Errors: []int{http.StatusBadRequest}}
+	if schemas.body != nil {
+		schema.Request = schemas.body
+	}
+	if schemas.query != nil {
+		schema.Query = schemas.query
+	}
+	return schema
+}
+
+// schemasFor 返回请求方法对应的 schema，复用的算子不处理该方法时不校验
+func (o *SchemaValidatorOperator) schemasFor(method string) *methodSchemas {
+	if o.byMethod == nil {
+		return &methodSchemas{body: o.body, query: o.query}
+	}
+	if schemas, ok := o.byMethod[method]; ok {
+		return schemas
+	}
+	return &methodSchemas{}
+}
+
+func (o *SchemaValidatorOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
+	schemas := o.schemasFor(r.Method)
+
+	var details []types.FieldError
+	if schemas.body != nil {
+		body := framework.RequestBody(ctx)
+		switch {
+		case len(body) > 0:
+			details = append(details, schemas.body.ValidateJSON(body)...)
+		case !o.allowEmptyBody:
+			details = append(details, types.FieldError{In: "body", Message: "request body is required"})
+		}
+	}
+	if schemas.query != nil {
+		details = append(details, schemas.query.ValidateQuery(r.Query)...)
+	}
+
+	if len(details) > 0 {
+		return ctx, &framework.OperatorResult{Error: types.NewValidationError(details)}
+	}
+	return ctx, &framework.OperatorResult{}
+}
@@ -1,12 +1,13 @@
 package operators
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"encoding/csv"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/zhanghuachuan/water-reminder/database"
 	"github.com/zhanghuachuan/water-reminder/framework"
 	"github.com/zhanghuachuan/water-reminder/types"
 	"github.com/zhanghuachuan/water-reminder/utils"
@@ -19,10 +20,15 @@ func (o *StatisticsOperator) Name() string {
 }
 
 type StatisticsRequest struct {
-	Period string `json:"period"` // day/week/month/custom
-	Date   string `json:"date"`   // 基准日期，格式 YYYY-MM-DD
-	Start  string `json:"start"`  // 自定义开始日期（当period=custom时使用）
-	End    string `json:"end"`    // 自定义结束日期（当period=custom时使用）
+	Period string `json:"period" schema:"enum=day|week|month|custom"` // day/week/month/custom
+	Date   string `json:"date" schema:"format=date"`                  // 基准日期，格式 YYYY-MM-DD
+	Start  string `json:"start" schema:"format=date"`                 // 自定义开始日期（当period=custom时使用）
+	End    string `json:"end" schema:"format=date"`                   // 自定义结束日期（当period=custom时使用）
+}
+
+// StatisticsQuery 统计接口的查询参数
+type StatisticsQuery struct {
+	Format string `json:"format" schema:"enum=csv"` // csv 时导出明细记录
 }
 
 type StatisticsResponse struct {
@@ -46,20 +52,35 @@ type WaterRecordInfo struct {
 	DrinkType string    `json:"drinkType"`
 }
 
-func (o *StatisticsOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// Schema 请求体可为空，GET 路由使用默认参数
+func (o *StatisticsOperator) Schema(method string) *framework.OperatorSchema {
+	return &framework.OperatorSchema{
+		Summary:  "饮水统计",
+		Request:  StatisticsRequest{},
+		Query:    StatisticsQuery{},
+		Response: StatisticsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	}
+}
+
+// Idempotent 统计查询只读，可安全重复执行
+func (o *StatisticsOperator) Idempotent() bool {
+	return true
+}
+
+func (o *StatisticsOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	// 获取当前用户
-	user, ok := ctx.Value("user").(*utils.User)
+	user, ok := framework.Get(ctx, UserKey)
 	if !ok || user == nil {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Unauthorized", "Unauthorized", http.StatusUnauthorized),
 		}
 	}
 
+	// 请求体为空时使用默认参数（当天统计），便于 GET 路由复用
 	var req StatisticsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Invalid request body", "BadRequest", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &req, framework.DecodeOptions{AllowEmpty: true}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 验证请求参数
@@ -100,14 +121,42 @@ func (o *StatisticsOperator) Execute(ctx context.Context, r *http.Request) (cont
 	}
 
 	// 处理统计数据
-	response := o.generateStatistics(req, user)
+	response := o.generateStatistics(ctx, req, user)
 
+	// format=csv 时以CSV导出明细记录，不使用JSON包装
+	if r.Query.Get("format") == "csv" {
+		return ctx, &framework.OperatorResult{
+			Data: response,
+			Response: framework.NewResponse(http.StatusOK).
+				SetHeader("Content-Disposition", "attachment; filename=\"statistics.csv\"").
+				RawBody("text/csv; charset=utf-8", o.recordsCSV(response.Records)),
+		}
+	}
+
+	// 统计结果与用户相关，只允许客户端私有缓存
 	return ctx, &framework.OperatorResult{
-		Data: response,
+		Data:     response,
+		Response: framework.NewResponse(http.StatusOK).SetHeader("Cache-Control", "private, max-age=60"),
+	}
+}
+
+// recordsCSV 将明细记录导出为CSV
+func (o *StatisticsOperator) recordsCSV(records []WaterRecordInfo) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"time", "amount", "drinkType"})
+	for _, record := range records {
+		writer.Write([]string{
+			record.Time.Format(time.RFC3339),
+			strconv.FormatFloat(record.Amount, 'f', -1, 64),
+			record.DrinkType,
+		})
 	}
+	writer.Flush()
+	return buf.Bytes()
 }
 
-func (o *StatisticsOperator) generateStatistics(req StatisticsRequest, user *utils.User) StatisticsResponse {
+func (o *StatisticsOperator) generateStatistics(ctx context.Context, req StatisticsRequest, user *utils.User) StatisticsResponse {
 	// 解析日期范围
 	baseDate, _ := time.Parse("2006-01-02", req.Date)
 	startTime := baseDate
@@ -130,7 +179,7 @@ func (o *StatisticsOperator) generateStatistics(req StatisticsRequest, user *uti
 		Amount     float64
 		DrinkType  string
 	}
-	db := database.GetDB()
+	db := dbFor(ctx)
 	db.Table("water_records").
 		Select("record_time, amount, drink_type").
 		Where("user_id = ? AND record_time BETWEEN ? AND ?",
@@ -0,0 +1,41 @@
+package operators
+
+import (
+	"context"
+
+	"github.com/zhanghuachuan/water-reminder/database"
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"gorm.io/gorm"
+)
+
+// gormTransaction 将 GORM 事务适配为调度器的路由级事务
+type gormTransaction struct {
+	tx *gorm.DB
+}
+
+func (t *gormTransaction) Commit() error {
+	return t.tx.Commit().Error
+}
+
+func (t *gormTransaction) Rollback() error {
+	return t.tx.Rollback().Error
+}
+
+// beginTransaction 为 transactional 路由开启 GORM 事务
+func beginTransaction(ctx context.Context) (framework.Transaction, error) {
+	tx := database.GetDB().WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &gormTransaction{tx: tx}, nil
+}
+
+// dbFor 返回算子应使用的数据库句柄：路由开启了事务时使用事务，否则使用全局连接
+func dbFor(ctx context.Context) *gorm.DB {
+	if tx, ok := framework.Get(ctx, framework.TxKey); ok {
+		if gormTx, ok := tx.(*gormTransaction); ok {
+			return gormTx.tx.WithContext(ctx)
+		}
+	}
+	return database.GetDB().WithContext(ctx)
+}
@@ -9,31 +9,64 @@ import (
 	"github.com/zhanghuachuan/water-reminder/utils"
 )
 
-type ValidatorOperator struct{}
+type ValidatorOperator struct {
+	AllowedMethods []string // 允许的请求方法，为空时允许 GET/POST
+	SkipJSONCheck  bool     // 不校验 Content-Type（如无请求体的 GET 路由）
+}
+
+// ValidatorConfig 校验算子的节点配置
+type ValidatorConfig struct {
+	AllowedMethods []string `json:"allowed_methods"`
+	SkipJSONCheck  bool     `json:"skip_json_check"`
+}
+
+// NewValidatorOperator 按节点配置创建校验算子
+func NewValidatorOperator(config map[string]interface{}) (framework.Operator, error) {
+	var cfg ValidatorConfig
+	if err := framework.DecodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	return &ValidatorOperator{
+		AllowedMethods: cfg.AllowedMethods,
+		SkipJSONCheck:  cfg.SkipJSONCheck,
+	}, nil
+}
 
 func (o *ValidatorOperator) Name() string {
 	return "validate"
 }
 
-func (o *ValidatorOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// Schema 校验算子只声明可能返回的错误
+func (o *ValidatorOperator) Schema(method string) *framework.OperatorSchema {
+	errors := []int{http.StatusMethodNotAllowed, http.StatusUnauthorized}
+	if !o.SkipJSONCheck {
+		errors = append(errors, http.StatusUnsupportedMediaType)
+	}
+	return &framework.OperatorSchema{Errors: errors}
+}
+
+func (o *ValidatorOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	// 验证请求方法
-	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+	allowedMethods := o.AllowedMethods
+	if len(allowedMethods) == 0 {
+		allowedMethods = []string{http.MethodPost, http.MethodGet}
+	}
+	if !utils.Contains(allowedMethods, r.Method) {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("方法不允许", "Method not allowed", http.StatusMethodNotAllowed),
 		}
 	}
 
 	// 验证内容类型
-	if r.Header.Get("Content-Type") != "application/json" {
+	if !o.SkipJSONCheck && r.Metadata.Get("Content-Type") != "application/json" {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("不支持的媒体类型", "Unsupported media type", http.StatusUnsupportedMediaType),
 		}
 	}
 
-	// 验证JWT令牌（如果存在）
-	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
-		tokenString := authHeader[len("Bearer "):]
-		_, err := utils.ValidateJWT(tokenString)
+	// 验证JWT令牌（如果存在），非 Bearer 格式的 Authorization 视为无效令牌
+	if r.Identity.Token != "" || r.Metadata.Get("Authorization") != "" {
+		_, err := utils.ValidateJWT(r.Identity.Token)
 		if err != nil {
 			return ctx, &framework.OperatorResult{
 				Error: types.NewApiError("未授权", "Unauthorized", http.StatusUnauthorized),
@@ -2,7 +2,6 @@ package operators
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"time"
 
@@ -22,9 +21,9 @@ func (o *WaterRecordOperator) Name() string {
 }
 
 type WaterRecordRequest struct {
-	Amount    float64   `json:"amount"`    // 饮水量（毫升）
-	Time      time.Time `json:"time"`      // 饮水时间
-	DrinkType string    `json:"drinkType"` // 饮品类型（水/茶/咖啡等）
+	Amount    float64   `json:"amount" schema:"required,exclusiveMin=0"` // 饮水量（毫升）
+	Time      time.Time `json:"time"`                                    // 饮水时间
+	DrinkType string    `json:"drinkType"`                               // 饮品类型（水/茶/咖啡等）
 }
 
 type WaterRecordResponse struct {
@@ -34,9 +33,36 @@ type WaterRecordResponse struct {
 	DrinkType string    `json:"drinkType"`
 }
 
-func (o *WaterRecordOperator) Execute(ctx context.Context, r *http.Request) (context.Context, *framework.OperatorResult) {
+// WaterRecordQuery 查询饮水记录的参数
+type WaterRecordQuery struct {
+	Date string `json:"date" schema:"format=date"` // 查询日期，默认当天
+}
+
+// Schema POST 创建记录，GET 按日期查询记录
+func (o *WaterRecordOperator) Schema(method string) *framework.OperatorSchema {
+	switch method {
+	case http.MethodPost:
+		return &framework.OperatorSchema{
+			Summary:  "记录饮水",
+			Request:  WaterRecordRequest{},
+			Response: WaterRecordResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+		}
+	case http.MethodGet:
+		return &framework.OperatorSchema{
+			Summary:  "查询饮水记录",
+			Query:    WaterRecordQuery{},
+			Response: []WaterRecordResponse{},
+			Errors:   []int{http.StatusUnauthorized},
+		}
+	default:
+		return nil
+	}
+}
+
+func (o *WaterRecordOperator) Execute(ctx context.Context, r *framework.Request) (context.Context, *framework.OperatorResult) {
 	// 获取当前用户
-	user, ok := ctx.Value("user").(*utils.User)
+	user, ok := framework.Get(ctx, UserKey)
 	if !ok || user == nil {
 		return ctx, &framework.OperatorResult{
 			Error: types.NewApiError("Unauthorized", "Unauthorized", http.StatusUnauthorized),
@@ -55,12 +81,10 @@ func (o *WaterRecordOperator) Execute(ctx context.Context, r *http.Request) (con
 	}
 }
 
-func (o *WaterRecordOperator) handleCreateRecord(ctx context.Context, r *http.Request, user *utils.User) (context.Context, *framework.OperatorResult) {
+func (o *WaterRecordOperator) handleCreateRecord(ctx context.Context, r *framework.Request, user *utils.User) (context.Context, *framework.OperatorResult) {
 	var req WaterRecordRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return ctx, &framework.OperatorResult{
-			Error: types.NewApiError("Invalid request body", "BadRequest", http.StatusBadRequest),
-		}
+	if err := framework.DecodeBody(ctx, &req, framework.DecodeOptions{}); err != nil {
+		return ctx, &framework.OperatorResult{Error: err}
 	}
 
 	// 验证输入
@@ -83,9 +107,9 @@ func (o *WaterRecordOperator) handleCreateRecord(ctx context.Context, r *http.Re
 	}
 }
 
-func (o *WaterRecordOperator) handleGetRecords(ctx context.Context, r *http.Request, user *utils.User) (context.Context, *framework.OperatorResult) {
+func (o *WaterRecordOperator) handleGetRecords(ctx context.Context, r *framework.Request, user *utils.User) (context.Context, *framework.OperatorResult) {
 	// 解析查询参数
-	date := r.URL.Query().Get("date")
+	date := r.Query.Get("date")
 	if date == "" {
 		date = time.Now().Format("2006-01-02")
 	}
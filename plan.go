@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+)
+
+// runPlan 实现 plan 子命令：按已注册的算子检查调度配置并输出执行计划。
+// 诊断信息写到 stderr，便于将 dot/mermaid 输出直接重定向到文件；存在错误时返回非零退出码。
+func runPlan(args []string) int {
+	flags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	configPath := flags.String("config", schedulerConfigPath, "scheduler config file")
+	format := flags.String("format", "text", "output format: text, dot, mermaid or json")
+	strict := flags.Bool("strict", false, "treat warnings as errors")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	report, err := framework.NewScheduler().LintConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch *format {
+	case "text":
+		writePlanText(os.Stdout, report)
+	case "dot":
+		fmt.Fprint(os.Stdout, report.DOT())
+	case "mermaid":
+		fmt.Fprint(os.Stdout, report.Mermaid())
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %s\n", *format)
+		return 2
+	}
+
+	for _, diagnostic := range report.Diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
+	if report.HasErrors() || (*strict && len(report.Diagnostics) > 0) {
+		return 1
+	}
+	return 0
+}
+
+// writePlanText 按路由输出各层级的节点，同一层级的节点可并行执行
+func writePlanText(w io.Writer, report *framework.PlanReport) {
+	for _, route := range report.Routes {
+		method := route.Method
+		if method == "" {
+			method = "*"
+		}
+		fmt.Fprintf(w, "%s (%s %s, %s)\n", route.ServerName, method, route.Path, route.Mode)
+		for _, nodeID := range sortedKeys(route.Includes) {
+			fmt.Fprintf(w, "  include %s: %s\n", nodeID, route.Includes[nodeID])
+		}
+		if len(route.Levels) == 0 {
+			fmt.Fprintln(w, "  no execution plan")
+			continue
+		}
+		for i, level := range route.Levels {
+			fmt.Fprintf(w, "  level %d: %s\n", i, strings.Join(level, ", "))
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Dial 连接 trpc.yaml 中某个服务的监听地址，返回的连接用于 api/proto 生成的客户端，
+// 如 proto.NewAuthServiceClient(conn)
+func Dial(address string, options ...grpc.DialOption) (*grpc.ClientConn, error) {
+	options = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, options...)
+	return grpc.NewClient(address, options...)
+}
+
+// NewInProcessClient 通过内存连接 server 中名为 service 的服务，不经过网络，用于测试与同进程调用
+func NewInProcessClient(server *Server, service string, options ...grpc.DialOption) (*grpc.ClientConn, error) {
+	listener, err := server.inProcessListener(service)
+	if err != nil {
+		return nil, err
+	}
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}, options...)
+	return Dial("passthrough:///"+service, options...)
+}
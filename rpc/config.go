@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProtocolGRPC 目前支持的协议，trpc.yaml 中每个服务的 protocol 必须为 grpc
+const ProtocolGRPC = "grpc"
+
+// ServiceConfig trpc.yaml 中 server.service 的单个服务，每个服务在自己的地址上监听。
+// transport 与 max_packet_size 不使用：gRPC 固定基于 HTTP/2，消息大小由 max_recv_msg_size/max_send_msg_size 限制
+type ServiceConfig struct {
+	Name           string `yaml:"name"` // proto 中的完整服务名，如 water_reminder.auth.AuthService
+	IP             string `yaml:"ip"`
+	Port           int    `yaml:"port"`
+	Network        string `yaml:"network"`
+	Protocol       string `yaml:"protocol"`
+	Timeout        int    `yaml:"timeout"`           // 单次调用超时（毫秒），0 表示不限制
+	MaxRecvMsgSize int    `yaml:"max_recv_msg_size"` // 请求消息的大小上限（字节），0 使用 gRPC 默认值
+	MaxSendMsgSize int    `yaml:"max_send_msg_size"` // 响应消息的大小上限（字节），0 使用 gRPC 默认值
+}
+
+// Config trpc.yaml 配置
+type Config struct {
+	Server struct {
+		Service []ServiceConfig `yaml:"service"`
+	} `yaml:"server"`
+}
+
+// LoadConfig 读取 trpc.yaml
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpc config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse rpc config: %w", err)
+	}
+	if len(config.Server.Service) == 0 {
+		return nil, fmt.Errorf("rpc config declares no service")
+	}
+	seen := make(map[string]bool, len(config.Server.Service))
+	for _, service := range config.Server.Service {
+		if seen[service.Name] {
+			return nil, fmt.Errorf("duplicate service %s", service.Name)
+		}
+		seen[service.Name] = true
+		if service.Protocol != ProtocolGRPC {
+			return nil, fmt.Errorf("unsupported protocol %q for service %s: only %s is supported", service.Protocol, service.Name, ProtocolGRPC)
+		}
+		if service.Port <= 0 || service.Port > 65535 {
+			return nil, fmt.Errorf("invalid port %d for service %s", service.Port, service.Name)
+		}
+	}
+	return &config, nil
+}
+
+// Address 监听地址，如 "0.0.0.0:8000"
+func (s ServiceConfig) Address() string {
+	return net.JoinHostPort(s.IP, strconv.Itoa(s.Port))
+}
+
+// CallTimeout 单次调用超时
+func (s ServiceConfig) CallTimeout() time.Duration {
+	return time.Duration(s.Timeout) * time.Millisecond
+}
+
+// network 监听的网络类型，默认 tcp
+func (s ServiceConfig) network() string {
+	if s.Network == "" {
+		return "tcp"
+	}
+	return s.Network
+}
@@ -0,0 +1,302 @@
+// Package rpc 通过 gRPC 提供与 HTTP 相同的算子管道。
+//
+// 服务与消息定义见 api/proto。trpc.yaml 中的每个服务在自己的地址上监听，
+// 每个 RPC 方法对应一个调度器路由，请求与响应消息按 proto3 JSON 形式与路由的请求体、响应数据互相转换。
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// inProcessBufferSize 进程内连接的缓冲区大小
+const inProcessBufferSize = 1 << 20
+
+// Server gRPC 服务端，trpc.yaml 中的每个服务对应一个 gRPC 服务器与监听地址
+type Server struct {
+	endpoints map[string]*endpoint
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// endpoint 单个服务的 gRPC 服务器
+type endpoint struct {
+	config    ServiceConfig
+	grpc      *grpc.Server
+	inProcess *bufconn.Listener // NewInProcessClient 首次调用时创建
+}
+
+// NewServer 按 trpc.yaml 中的服务配置创建服务端，服务名必须是 api/proto 中定义的服务
+func NewServer(scheduler *framework.Scheduler, services []ServiceConfig) (*Server, error) {
+	s := &Server{endpoints: make(map[string]*endpoint, len(services))}
+	for _, config := range services {
+		register, ok := registrations[config.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown rpc service %s", config.Name)
+		}
+
+		var options []grpc.ServerOption
+		if config.MaxRecvMsgSize > 0 {
+			options = append(options, grpc.MaxRecvMsgSize(config.MaxRecvMsgSize))
+		}
+		if config.MaxSendMsgSize > 0 {
+			options = append(options, grpc.MaxSendMsgSize(config.MaxSendMsgSize))
+		}
+		server := grpc.NewServer(options...)
+		register(server, &dispatcher{scheduler: scheduler, config: config})
+		s.endpoints[config.Name] = &endpoint{config: config, grpc: server}
+	}
+	return s, nil
+}
+
+// ListenAndServe 在每个服务的地址上监听，任一服务停止时返回；Close 后返回 nil
+func (s *Server) ListenAndServe() error {
+	listeners := make(map[*endpoint]net.Listener, len(s.endpoints))
+	for _, e := range s.endpoints {
+		listener, err := net.Listen(e.config.network(), e.config.Address())
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return fmt.Errorf("failed to listen for %s: %w", e.config.Name, err)
+		}
+		listeners[e] = listener
+	}
+
+	errs := make(chan error, len(listeners))
+	for e, listener := range listeners {
+		go func(e *endpoint, listener net.Listener) {
+			errs <- e.grpc.Serve(listener)
+		}(e, listener)
+	}
+	err := <-errs
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// Addresses 各服务的监听地址
+func (s *Server) Addresses() map[string]string {
+	addresses := make(map[string]string, len(s.endpoints))
+	for name, e := range s.endpoints {
+		addresses[name] = e.config.Address()
+	}
+	return addresses
+}
+
+// Close 停止全部服务，进行中的调用被取消
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, e := range s.endpoints {
+		e.grpc.Stop()
+	}
+	return nil
+}
+
+// inProcessListener 返回服务的进程内监听器，首次调用时开始在其上提供服务
+func (s *Server) inProcessListener(service string) (*bufconn.Listener, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, grpc.ErrServerStopped
+	}
+	e, ok := s.endpoints[service]
+	if !ok {
+		return nil, fmt.Errorf("rpc service %s is not configured", service)
+	}
+	if e.inProcess == nil {
+		e.inProcess = bufconn.Listen(inProcessBufferSize)
+		go e.grpc.Serve(e.inProcess)
+	}
+	return e.inProcess, nil
+}
+
+// route RPC 方法对应的调度器路由
+type route struct {
+	serverName string
+	method     string // 路由的请求方法；GET 时请求消息的字段作为查询参数，否则作为 JSON 请求体
+	field      string // 路由响应数据对应的响应消息字段，为空表示整个响应消息
+}
+
+// dispatcher 将 gRPC 调用转换为 framework.Request 并执行对应路由
+type dispatcher struct {
+	scheduler *framework.Scheduler
+	config    ServiceConfig
+}
+
+// call 执行路由并将响应数据解码到 out。
+// 上下文派生自 gRPC 调用：客户端断开或截止时间到达时取消执行中的算子；路由自身的超时由调度器叠加
+func (d *dispatcher) call(ctx context.Context, r route, in, out proto.Message) error {
+	if timeout := d.config.CallTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	request, err := newRequest(ctx, r, in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	output, err := d.scheduler.Run(ctx, r.serverName, framework.ExecuteOptions{Request: request})
+	if output == nil {
+		return status.Errorf(codes.Unimplemented, "route %s is not configured: %v", r.serverName, err)
+	}
+	for _, result := range output.Results {
+		if result.Error != nil {
+			return statusError(result.Error)
+		}
+	}
+	if err != nil {
+		return statusError(err)
+	}
+
+	if resp := output.Response; resp != nil && len(resp.Header) > 0 {
+		header := metadata.MD{}
+		for key, values := range resp.Header {
+			header.Append(strings.ToLower(key), values...)
+		}
+		grpc.SetHeader(ctx, header)
+	}
+	return decodeResponse(output.Body, r.field, out)
+}
+
+// newRequest 将 gRPC 调用适配为请求：metadata 作为请求元数据，请求消息按路由方法作为请求体或查询参数
+func newRequest(ctx context.Context, r route, in proto.Message) (*framework.Request, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	attrs := make(map[string]string, len(md))
+	for key, values := range md {
+		// 跳过 :authority 等伪头，content-type 为 application/grpc，由请求体的格式决定
+		if strings.HasPrefix(key, ":") || key == "content-type" || len(values) == 0 {
+			continue
+		}
+		attrs[key] = values[0]
+	}
+
+	data, err := protojson.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *framework.Request
+	if r.method == http.MethodGet {
+		request = framework.NewRPCRequest(r.method, r.serverName, attrs, nil)
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		for name, value := range fields {
+			request.Query.Set(name, fmt.Sprint(value))
+		}
+	} else {
+		request = framework.NewRPCRequest(r.method, r.serverName, attrs, data)
+	}
+	// 请求体统一为 JSON，与 HTTP 接口一致
+	request.Metadata.Set("Content-Type", "application/json")
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		request.RemoteAddr = p.Addr.String()
+	}
+	return request, nil
+}
+
+// decodeResponse 按 proto3 JSON 规则将路由响应数据解码为响应消息，忽略消息中未定义的字段
+func decodeResponse(body interface{}, field string, out proto.Message) error {
+	if body == nil {
+		return nil
+	}
+	if field != "" {
+		body = map[string]interface{}{field: body}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, out); err != nil {
+		return status.Errorf(codes.Internal, "failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// statusError 将 ApiError 的 HTTP 状态码转换为 gRPC 状态码，字段校验错误以 BadRequest 详情返回
+func statusError(err error) error {
+	var apiErr *types.ApiError
+	if !errors.As(err, &apiErr) {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	st := status.New(grpcCode(apiErr.StatusCode), apiErr.Error())
+	if len(apiErr.Details) == 0 {
+		return st.Err()
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(apiErr.Details))
+	for _, detail := range apiErr.Details {
+		field := detail.In
+		if detail.Field != "" {
+			field += "." + detail.Field
+		}
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: detail.Message})
+	}
+	if withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// grpcCode HTTP 状态码对应的 gRPC 状态码
+func grpcCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case statusCode >= 500:
+		return codes.Internal
+	case statusCode >= 400:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,266 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/zhanghuachuan/water-reminder/api/proto"
+	"github.com/zhanghuachuan/water-reminder/framework"
+	"github.com/zhanghuachuan/water-reminder/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// funcOperator 以函数实现的测试算子
+type funcOperator struct {
+	name    string
+	execute func(ctx context.Context, req *framework.Request) *framework.OperatorResult
+}
+
+func (o *funcOperator) Name() string { return o.name }
+
+func (o *funcOperator) Execute(ctx context.Context, req *framework.Request) (context.Context, *framework.OperatorResult) {
+	return ctx, o.execute(ctx, req)
+}
+
+const testConfig = `[
+  {"server_name": "/login", "nodes": [{"name": "rpc_test_login"}]},
+  {"server_name": "/get_water_records", "method": "GET", "nodes": [{"name": "rpc_test_records"}]},
+  {"server_name": "/auth", "nodes": [{"name": "rpc_test_invalid"}]},
+  {"server_name": "/dashboard", "method": "GET", "nodes": [{"name": "rpc_test_slow"}]}
+]`
+
+// slowCanceled 收到 rpc_test_slow 观察到的取消原因
+var slowCanceled = make(chan error, 1)
+
+func init() {
+	framework.RegisterOperator("rpc_test_login", &funcOperator{name: "rpc_test_login", execute: func(ctx context.Context, req *framework.Request) *framework.OperatorResult {
+		var login struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}
+		if err := framework.DecodeBody(ctx, &login, framework.DecodeOptions{Strict: true}); err != nil {
+			return &framework.OperatorResult{Error: err}
+		}
+		if req.Method != http.MethodPost || req.Metadata.Get("Content-Type") != "application/json" || req.RemoteAddr == "" {
+			return &framework.OperatorResult{Error: types.NewApiError("bad request", req.Method+" "+req.Metadata.Get("Content-Type")+" "+req.RemoteAddr, http.StatusBadRequest)}
+		}
+		return &framework.OperatorResult{
+			Data: &types.LoginResponseData{
+				Token: req.Identity.Token,
+				User:  &types.User{ID: "u1", Email: login.Email, Username: "alice"},
+			},
+			Response: framework.NewResponse(http.StatusOK).SetHeader("X-Cache", "MISS"),
+		}
+	}})
+	framework.RegisterOperator("rpc_test_records", &funcOperator{name: "rpc_test_records", execute: func(ctx context.Context, req *framework.Request) *framework.OperatorResult {
+		return &framework.OperatorResult{Data: []map[string]interface{}{
+			{"id": "r1", "amount": 250, "time": time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), "drinkType": req.Query.Get("date")},
+		}}
+	}})
+	framework.RegisterOperator("rpc_test_invalid", &funcOperator{name: "rpc_test_invalid", execute: func(ctx context.Context, req *framework.Request) *framework.OperatorResult {
+		return &framework.OperatorResult{Error: types.NewValidationError([]types.FieldError{
+			{In: "body", Field: "amount", Message: "must be > 0"},
+			{In: "query", Field: "date", Message: "must be a date in YYYY-MM-DD format"},
+		})}
+	}})
+	framework.RegisterOperator("rpc_test_slow", &funcOperator{name: "rpc_test_slow", execute: func(ctx context.Context, req *framework.Request) *framework.OperatorResult {
+		<-ctx.Done()
+		slowCanceled <- ctx.Err()
+		return &framework.OperatorResult{Error: ctx.Err()}
+	}})
+}
+
+// newTestServer 创建按 testConfig 执行路由的服务端，并返回 service 的进程内连接
+func newTestServer(t *testing.T, service string, timeout int) *grpc.ClientConn {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "scheduler_config.json")
+	if err := os.WriteFile(path, []byte(testConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sched := framework.NewScheduler()
+	if err := sched.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewServer(sched, []ServiceConfig{
+		{Name: pb.AuthService_ServiceDesc.ServiceName, Protocol: ProtocolGRPC, Port: 8000, Timeout: timeout},
+		{Name: pb.RecordService_ServiceDesc.ServiceName, Protocol: ProtocolGRPC, Port: 8001, Timeout: timeout},
+		{Name: pb.StatisticsService_ServiceDesc.ServiceName, Protocol: ProtocolGRPC, Port: 8003, Timeout: timeout},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := NewInProcessClient(server, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestLoginRoundTrip(t *testing.T) {
+	conn := newTestServer(t, pb.AuthService_ServiceDesc.ServiceName, 0)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token-1")
+	var header metadata.MD
+	resp, err := pb.NewAuthServiceClient(conn).Login(ctx,
+		&pb.LoginRequest{Email: "alice@example.com", Password: "secret"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if resp.Token != "token-1" || resp.User.GetId() != "u1" || resp.User.GetEmail() != "alice@example.com" {
+		t.Errorf("unexpected response %v", resp)
+	}
+	if got := header.Get("x-cache"); len(got) != 1 || got[0] != "MISS" {
+		t.Errorf("x-cache header = %v, want [MISS]", got)
+	}
+}
+
+func TestGetRouteUsesQueryAndResponseField(t *testing.T) {
+	conn := newTestServer(t, pb.RecordService_ServiceDesc.ServiceName, 0)
+
+	resp, err := pb.NewRecordServiceClient(conn).ListRecords(context.Background(), &pb.ListRecordsRequest{Date: "2024-05-01"})
+	if err != nil {
+		t.Fatalf("ListRecords: %v", err)
+	}
+	if len(resp.Records) != 1 {
+		t.Fatalf("got %d records, want 1", len(resp.Records))
+	}
+	record := resp.Records[0]
+	if record.Id != "r1" || record.Amount != 250 || record.DrinkType != "2024-05-01" {
+		t.Errorf("unexpected record %v", record)
+	}
+	if got := record.Time.AsTime(); !got.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("time = %v", got)
+	}
+}
+
+func TestValidationErrorDetails(t *testing.T) {
+	conn := newTestServer(t, pb.AuthService_ServiceDesc.ServiceName, 0)
+
+	_, err := pb.NewAuthServiceClient(conn).Auth(context.Background(), &pb.AuthRequest{})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument (%v)", st.Code(), err)
+	}
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+	if got := strings.Join(fields, ","); got != "body.amount,query.date" {
+		t.Errorf("field violations = %q", got)
+	}
+}
+
+func TestDeadlinePropagatesToOperators(t *testing.T) {
+	tests := []struct {
+		name          string
+		serviceMillis int
+		clientTimeout time.Duration
+	}{
+		{name: "client deadline", clientTimeout: 50 * time.Millisecond},
+		{name: "service timeout", serviceMillis: 50, clientTimeout: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newTestServer(t, pb.StatisticsService_ServiceDesc.ServiceName, tt.serviceMillis)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.clientTimeout)
+			defer cancel()
+			_, err := pb.NewStatisticsServiceClient(conn).GetDashboard(ctx, &pb.DashboardRequest{})
+			if code := status.Code(err); code != codes.DeadlineExceeded {
+				t.Errorf("code = %v, want DeadlineExceeded (%v)", code, err)
+			}
+
+			select {
+			case err := <-slowCanceled:
+				if err != context.DeadlineExceeded && err != context.Canceled {
+					t.Errorf("operator context error = %v", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("operator context was not canceled")
+			}
+		})
+	}
+}
+
+func TestUnconfiguredService(t *testing.T) {
+	sched := framework.NewScheduler()
+	server, err := NewServer(sched, []ServiceConfig{{Name: pb.AuthService_ServiceDesc.ServiceName, Protocol: ProtocolGRPC, Port: 8000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	if _, err := NewInProcessClient(server, pb.RecordService_ServiceDesc.ServiceName); err == nil {
+		t.Error("expected error for a service missing from the config")
+	}
+	if _, err := NewServer(sched, []ServiceConfig{{Name: "water_reminder.Unknown", Protocol: ProtocolGRPC, Port: 8000}}); err == nil {
+		t.Error("expected error for an unknown service name")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "grpc services",
+			config: "server:\n  service:\n    - {name: a, port: 8000, protocol: grpc}\n    - {name: b, port: 8001, protocol: grpc}\n",
+		},
+		{
+			name:    "trpc protocol",
+			config:  "server:\n  service:\n    - {name: a, port: 8000, protocol: trpc}\n",
+			wantErr: `unsupported protocol "trpc"`,
+		},
+		{
+			name:    "duplicate service",
+			config:  "server:\n  service:\n    - {name: a, port: 8000, protocol: grpc}\n    - {name: a, port: 8001, protocol: grpc}\n",
+			wantErr: "duplicate service a",
+		},
+		{
+			name:    "invalid port",
+			config:  "server:\n  service:\n    - {name: a, port: 0, protocol: grpc}\n",
+			wantErr: "invalid port",
+		},
+		{
+			name:    "no service",
+			config:  "server: {}\n",
+			wantErr: "declares no service",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trpc.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadConfig(path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	pb "github.com/zhanghuachuan/water-reminder/api/proto"
+	"google.golang.org/grpc"
+)
+
+// registrations 按 trpc.yaml 中的服务名注册 api/proto 中定义的服务
+var registrations = map[string]func(grpc.ServiceRegistrar, *dispatcher){
+	pb.AuthService_ServiceDesc.ServiceName: func(r grpc.ServiceRegistrar, d *dispatcher) {
+		pb.RegisterAuthServiceServer(r, &authService{d: d})
+	},
+	pb.RecordService_ServiceDesc.ServiceName: func(r grpc.ServiceRegistrar, d *dispatcher) {
+		pb.RegisterRecordServiceServer(r, &recordService{d: d})
+	},
+	pb.ReminderConfigService_ServiceDesc.ServiceName: func(r grpc.ServiceRegistrar, d *dispatcher) {
+		pb.RegisterReminderConfigServiceServer(r, &reminderConfigService{d: d})
+	},
+	pb.StatisticsService_ServiceDesc.ServiceName: func(r grpc.ServiceRegistrar, d *dispatcher) {
+		pb.RegisterStatisticsServiceServer(r, &statisticsService{d: d})
+	},
+}
+
+// authService 对应 api/proto/auth.proto
+type authService struct {
+	pb.UnimplementedAuthServiceServer
+	d *dispatcher
+}
+
+func (s *authService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	resp := &pb.LoginResponse{}
+	if err := s.d.call(ctx, route{serverName: "/login", method: http.MethodPost}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *authService) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.LoginResponse, error) {
+	resp := &pb.LoginResponse{}
+	if err := s.d.call(ctx, route{serverName: "/register", method: http.MethodPost}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *authService) Auth(ctx context.Context, req *pb.AuthRequest) (*pb.AuthResponse, error) {
+	resp := &pb.AuthResponse{}
+	if err := s.d.call(ctx, route{serverName: "/auth", method: http.MethodPost}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordService 对应 api/proto/record.proto
+type recordService struct {
+	pb.UnimplementedRecordServiceServer
+	d *dispatcher
+}
+
+func (s *recordService) CreateRecord(ctx context.Context, req *pb.CreateRecordRequest) (*pb.WaterRecord, error) {
+	resp := &pb.WaterRecord{}
+	if err := s.d.call(ctx, route{serverName: "/create_drinking_record", method: http.MethodPost}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *recordService) ListRecords(ctx context.Context, req *pb.ListRecordsRequest) (*pb.ListRecordsResponse, error) {
+	resp := &pb.ListRecordsResponse{}
+	if err := s.d.call(ctx, route{serverName: "/get_water_records", method: http.MethodGet, field: "records"}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// reminderConfigService 对应 api/proto/reminder_config.proto
+type reminderConfigService struct {
+	pb.UnimplementedReminderConfigServiceServer
+	d *dispatcher
+}
+
+func (s *reminderConfigService) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.ReminderConfig, error) {
+	resp := &pb.ReminderConfig{}
+	if err := s.d.call(ctx, route{serverName: "/get_reminder_config", method: http.MethodGet}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *reminderConfigService) UpdateConfig(ctx context.Context, req *pb.ReminderConfig) (*pb.ReminderConfig, error) {
+	resp := &pb.ReminderConfig{}
+	if err := s.d.call(ctx, route{serverName: "/update_reminder_config", method: http.MethodPut}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// statisticsService 对应 api/proto/statistics.proto
+type statisticsService struct {
+	pb.UnimplementedStatisticsServiceServer
+	d *dispatcher
+}
+
+func (s *statisticsService) GetStatistics(ctx context.Context, req *pb.StatisticsRequest) (*pb.StatisticsResponse, error) {
+	resp := &pb.StatisticsResponse{}
+	if err := s.d.call(ctx, route{serverName: "/get_statistics", method: http.MethodPost}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *statisticsService) GetDashboard(ctx context.Context, req *pb.DashboardRequest) (*pb.DashboardResponse, error) {
+	resp := &pb.DashboardResponse{}
+	if err := s.d.call(ctx, route{serverName: "/dashboard", method: http.MethodGet}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -1,18 +1,29 @@
 package types
 
+import "net/http"
+
 // 统一API响应格式
 type ApiResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
 }
 
 // ApiError 用于算子返回的错误信息
 type ApiError struct {
-	Message    string `json:"message"`
-	ErrorMsg   string `json:"errorMsg"`
-	StatusCode int    `json:"statusCode"`
+	Message    string       `json:"message"`
+	ErrorMsg   string       `json:"errorMsg"`
+	StatusCode int          `json:"statusCode"`
+	Details    []FieldError `json:"details,omitempty"` // 参数校验失败的字段
+}
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	In      string `json:"in"`      // body 或 query
+	Field   string `json:"field"`   // 字段路径，如 records[0].amount，为空表示整个请求体
+	Message string `json:"message"` // 违反的约束
 }
 
 // Error 实现error接口
@@ -29,6 +40,16 @@ func NewApiError(message, errorMsg string, statusCode int) *ApiError {
 	}
 }
 
+// 创建参数校验错误，列出每个不合法的字段
+func NewValidationError(details []FieldError) *ApiError {
+	return &ApiError{
+		Message:    "参数验证失败",
+		ErrorMsg:   "Request validation failed",
+		StatusCode: http.StatusBadRequest,
+		Details:    details,
+	}
+}
+
 // 登录成功响应数据
 type LoginResponseData struct {
 	Token string `json:"token"`
@@ -52,3 +73,10 @@ func NewErrorResponse(message string, errorMsg string) *ApiResponse {
 		Error:   errorMsg,
 	}
 }
+
+// 由ApiError创建错误响应，保留字段校验错误
+func NewApiErrorResponse(err *ApiError) *ApiResponse {
+	resp := NewErrorResponse(err.Message, err.ErrorMsg)
+	resp.Details = err.Details
+	return resp
+}
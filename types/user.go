@@ -13,10 +13,10 @@ type ReminderConfig struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	UserID      string    `gorm:"not null" json:"userId"`
 	Enabled     bool      `gorm:"not null;default:true" json:"enabled"`
-	StartTime   time.Time `gorm:"not null" json:"startTime"`   // 提醒开始时间(每天)
-	EndTime     time.Time `gorm:"not null" json:"endTime"`     // 提醒结束时间(每天)
-	Interval    int       `gorm:"not null" json:"interval"`    // 提醒间隔(分钟)
-	DailyTarget int       `gorm:"not null" json:"dailyTarget"` // 每日目标(毫升)
+	StartTime   time.Time `gorm:"not null" json:"startTime"`                                    // 提醒开始时间(每天)
+	EndTime     time.Time `gorm:"not null" json:"endTime"`                                      // 提醒结束时间(每天)
+	Interval    int       `gorm:"not null" json:"interval" schema:"required,min=15"`            // 提醒间隔(分钟)
+	DailyTarget int       `gorm:"not null" json:"dailyTarget" schema:"required,exclusiveMin=0"` // 每日目标(毫升)
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
 }